@@ -0,0 +1,12 @@
+terraform {
+  required_version = ">= 1.8.0"
+  required_providers {
+    objectscale = {
+      source = "registry.terraform.io/dell/objectscale"
+    }
+  }
+}
+
+output "gib_in_gb" {
+  value = provider::objectscale::convert_size(1, "GiB", "GB")
+}
@@ -0,0 +1,12 @@
+terraform {
+  required_version = ">= 1.8.0"
+  required_providers {
+    objectscale = {
+      source = "registry.terraform.io/dell/objectscale"
+    }
+  }
+}
+
+output "replication_group" {
+  value = provider::objectscale::parse_urn("urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global").uuid
+}
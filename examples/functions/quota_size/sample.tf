@@ -0,0 +1,12 @@
+terraform {
+  required_version = ">= 1.8.0"
+  required_providers {
+    objectscale = {
+      source = "registry.terraform.io/dell/objectscale"
+    }
+  }
+}
+
+output "block_size" {
+  value = provider::objectscale::quota_size(500, "GiB")
+}
@@ -1 +1,4 @@
 terraform import objectscale_namespace.example "luis_namespace"
+
+# A namespace whose calls are sent to another endpoint, ex. the VDC owning it in a geo-federation
+terraform import objectscale_namespace.remote "https://vdc2.example.com:4443,remote_namespace"
@@ -30,4 +30,8 @@ resource "objectscale_namespace" "example" {
     domain =  "domain"
     groups = ["group"]
   }]
+  timeouts {
+    create = "30m"
+    delete = "30m"
+  }
 }
@@ -0,0 +1,48 @@
+package client
+
+import "context"
+
+// NamespaceAPI covers the namespace operations of the management API.
+type NamespaceAPI interface {
+	// CreateNamespace creates a namespace.
+	CreateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error)
+	// GetNamespace returns the namespace with the given identifier.
+	GetNamespace(ctx context.Context, id string) (*Namespace, error)
+	// UpdateNamespace updates the namespace identified by namespace.Id.
+	UpdateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error)
+	// DeleteNamespace deletes the namespace with the given identifier, which is also its name.
+	DeleteNamespace(ctx context.Context, id string) error
+	// ListNamespaces returns the namespaces matching the name, all of them when it is empty.
+	ListNamespaces(ctx context.Context, name string) ([]Namespace, error)
+}
+
+// ManagementAPI is the management API used by the data sources and resources.
+// Backends, fakes and decorators implement it; the operations on further kinds
+// of objects are added as interfaces embedded here.
+type ManagementAPI interface {
+	NamespaceAPI
+}
+
+// ClusterPolicy is implemented by the ManagementAPI enforcing the settings of the
+// provider, ex. Client. The resources assert it to report refusals when planning.
+type ClusterPolicy interface {
+	// CheckNamespaceDeletion returns the error DeleteNamespace would refuse the namespace with.
+	CheckNamespaceDeletion(name string) error
+	// Version returns the release of the cluster, unknown if it could not be detected.
+	Version() Version
+}
+
+// EndpointAPI is implemented by the ManagementAPI able to address another endpoint,
+// ex. the VDC owning an object in a geo-federation.
+type EndpointAPI interface {
+	// ForEndpoint returns the ManagementAPI sending the calls to the endpoint,
+	// logged in with the same credentials.
+	ForEndpoint(endpoint string) (ManagementAPI, error)
+}
+
+// Ensure Client can stand in for any backend.
+var (
+	_ ManagementAPI = &Client{}
+	_ ClusterPolicy = &Client{}
+	_ EndpointAPI   = &Client{}
+)
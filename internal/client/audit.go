@@ -0,0 +1,247 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Outcomes of an audited call.
+const (
+	auditOutcomeSuccess = "success"
+	auditOutcomeFailure = "failure"
+	// auditOutcomeRefused is a call refused by the provider before reaching the cluster.
+	auditOutcomeRefused = "refused"
+)
+
+// auditEntry is one line of the audit log. The entries are hash-chained: Hash is the
+// SHA-256 of PreviousHash and of the entry encoded without Hash, so editing, removing
+// or reordering lines breaks the chain from that line on. Removing the last lines leaves
+// a valid chain, only a copy of the last hash kept elsewhere detects it.
+type auditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Endpoint  string    `json:"endpoint"`
+	User      string    `json:"user"`
+	Operation string    `json:"operation"`
+	ObjectID  string    `json:"object_id"`
+	// Request is the redacted body of the call, if any.
+	Request   json.RawMessage `json:"request,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	Outcome   string          `json:"outcome"`
+	Error     string          `json:"error,omitempty"`
+
+	PreviousHash string `json:"previous_hash"`
+	Hash         string `json:"hash,omitempty"`
+}
+
+// auditLog appends an entry for every call changing the cluster. Several runs may share
+// the file, each entry is chained and appended while holding a lock on it.
+type auditLog struct {
+	// mu orders the entries of the process, each one is chained to the previous one.
+	mu   sync.Mutex
+	file *os.File
+	// lastHash is the hash of the last entry, and size the size of the file after it,
+	// as last read or written by the process.
+	lastHash string
+	size     int64
+}
+
+// openAuditLog opens the audit log for appending, continuing the chain of its last entry.
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the audit log: %w", err)
+	}
+
+	audit := &auditLog{file: file}
+	if err := audit.lockAndSync(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("unable to read the audit log %s: %w", path, err)
+	}
+	if err := unlockFile(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("unable to unlock the audit log %s: %w", path, err)
+	}
+	return audit, nil
+}
+
+// lockAndSync locks the file, then catches up with the entries appended by other runs
+// since the last entry the process read or wrote. The file stays locked unless it fails.
+func (a *auditLog) lockAndSync() error {
+	if err := lockFile(a.file); err != nil {
+		return err
+	}
+	info, err := a.file.Stat()
+	if err != nil {
+		_ = unlockFile(a.file)
+		return err
+	}
+	size := info.Size()
+	if size == a.size {
+		return nil
+	}
+
+	offset := a.size
+	if size < offset {
+		// Truncated, the chain continues from its new last entry.
+		offset = 0
+	}
+	hash, err := lastAuditHash(io.NewSectionReader(a.file, offset, size-offset))
+	if err != nil {
+		_ = unlockFile(a.file)
+		return err
+	}
+	if hash != "" || offset == 0 {
+		a.lastHash = hash
+	}
+	a.size = size
+	return nil
+}
+
+// lastAuditHash returns the hash of the last entry, empty for a new log.
+func lastAuditHash(r io.Reader) (string, error) {
+	var last []byte
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if last == nil {
+		return "", nil
+	}
+
+	var entry auditEntry
+	if err := json.Unmarshal(last, &entry); err != nil || entry.Hash == "" {
+		return "", errors.New("the last entry is not a valid audit entry")
+	}
+	return entry.Hash, nil
+}
+
+// hashAuditEntry returns the chained hash of the entry, ignoring its own Hash.
+func hashAuditEntry(entry auditEntry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(entry.PreviousHash+"\n"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// record appends the entry of a call served by the endpoint on behalf of the user. A failure to
+// write the log is reported in the provider logs only, the change it records is already made on
+// the cluster.
+func (a *auditLog) record(ctx context.Context, endpoint, user, operation, objectID string, request interface{}, err error) {
+	entry := auditEntry{
+		Timestamp: time.Now().UTC(),
+		Endpoint:  endpoint,
+		User:      user,
+		Operation: operation,
+		ObjectID:  objectID,
+		RequestID: contextRequestID(ctx),
+		Outcome:   auditOutcomeSuccess,
+	}
+	if request != nil {
+		if data, err := json.Marshal(request); err == nil {
+			entry.Request = json.RawMessage(redactBody(data))
+		}
+	}
+	switch {
+	case errors.Is(err, ErrReadOnly) || errors.Is(err, ErrProtectedNamespace):
+		entry.Outcome = auditOutcomeRefused
+		entry.Error = err.Error()
+	case err != nil:
+		entry.Outcome = auditOutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if writeErr := a.append(entry); writeErr != nil {
+		tflog.Error(ctx, "unable to write the audit log", map[string]interface{}{
+			"operation": operation,
+			"object_id": objectID,
+			"error":     writeErr.Error(),
+		})
+	}
+}
+
+// append chains the entry to the last one of the file and writes it, holding the lock on
+// the file so the other runs sharing it do not interleave.
+func (a *auditLog) append(entry auditEntry) error {
+	if err := a.lockAndSync(); err != nil {
+		return err
+	}
+	defer func() { _ = unlockFile(a.file) }()
+
+	entry.PreviousHash = a.lastHash
+	hash, err := hashAuditEntry(entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	n, err := a.file.Write(append(line, '\n'))
+	a.size += int64(n)
+	if err != nil {
+		return err
+	}
+	a.lastHash = hash
+	return nil
+}
+
+// VerifyAuditLog checks the hash chain of an audit log and returns the hash of its last
+// entry, or the line of the first broken entry.
+func VerifyAuditLog(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	previous := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry auditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return "", fmt.Errorf("line %d: invalid audit entry: %w", number, err)
+		}
+		hash, err := hashAuditEntry(entry)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %w", number, err)
+		}
+		if entry.PreviousHash != previous || entry.Hash != hash {
+			return "", fmt.Errorf("line %d: the hash chain is broken, the log was altered", number)
+		}
+		previous = entry.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return previous, nil
+}
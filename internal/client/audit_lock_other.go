@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package client
+
+import "os"
+
+// lockFile does nothing where files cannot be locked, the processes sharing an audit log
+// are then not serialized.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
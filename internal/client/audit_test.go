@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAuditLog records the entries of three calls, over two runs.
+func writeAuditLog(t *testing.T) (path string, lines []string) {
+	t.Helper()
+	path = filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+
+	audit, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.record(ctx, "https://a:4443", "root", "create", "ns1", &Namespace{Name: "ns1"}, nil)
+	audit.record(ctx, "https://a:4443", "root", "update", "ns1", &Namespace{Name: "ns1", BlockSize: 10}, errors.New("forbidden"))
+	_ = audit.file.Close()
+
+	// The next run continues the chain.
+	audit, err = openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.record(ctx, "https://a:4443", "root", "delete", "ns1", nil, ErrReadOnly)
+	_ = audit.file.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, strings.Split(strings.TrimSpace(string(content)), "\n")
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	path, lines := writeAuditLog(t)
+	if len(lines) != 3 {
+		t.Fatalf("got %d entries, want 3", len(lines))
+	}
+	lastHash, err := VerifyAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lines[2], `"hash":"`+lastHash+`"`) || !strings.Contains(lines[2], `"outcome":"refused"`) {
+		t.Errorf("got last hash %q for the last entry %s", lastHash, lines[2])
+	}
+
+	tests := []struct {
+		name     string
+		alter    func(lines []string) []string
+		wantLine string
+	}{
+		{
+			name: "edited line",
+			alter: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"outcome":"failure"`, `"outcome":"success"`, 1)
+				return lines
+			},
+			wantLine: "line 2:",
+		},
+		{
+			name:     "removed line",
+			alter:    func(lines []string) []string { return append(lines[:1], lines[2]) },
+			wantLine: "line 2:",
+		},
+		{
+			name:     "reordered lines",
+			alter:    func(lines []string) []string { return []string{lines[1], lines[0], lines[2]} },
+			wantLine: "line 1:",
+		},
+		{
+			name:     "invalid line",
+			alter:    func(lines []string) []string { return append(lines, "{") },
+			wantLine: "line 4:",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			altered := test.alter(append([]string(nil), lines...))
+			if err := os.WriteFile(path, []byte(strings.Join(altered, "\n")+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := VerifyAuditLog(path); err == nil || !strings.HasPrefix(err.Error(), test.wantLine) {
+				t.Errorf("got %v, want a failure at %s", err, test.wantLine)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditLogTruncatedTail(t *testing.T) {
+	path, lines := writeAuditLog(t)
+	lastHash, err := VerifyAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dropping the last entries leaves a valid chain, only the last hash changes.
+	if err := os.WriteFile(path, []byte(strings.Join(lines[:2], "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	truncatedHash, err := VerifyAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncatedHash == lastHash {
+		t.Error("the truncated log has the same last hash")
+	}
+}
+
+func TestAuditLogSharedByConcurrentRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+
+	// Each run has its own handle on the file, as separate processes do.
+	runs := make([]*auditLog, 2)
+	for i := range runs {
+		audit, err := openAuditLog(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer audit.file.Close()
+		runs[i] = audit
+	}
+
+	// The runs take turns, each one appending after the entries of the other.
+	for j := 0; j < 10; j++ {
+		for i, audit := range runs {
+			audit.record(ctx, "https://a:4443", "root", "update", fmt.Sprintf("ns%d-%d", i, j), nil, nil)
+		}
+	}
+
+	if _, err := VerifyAuditLog(path); err != nil {
+		t.Fatalf("the interleaved entries broke the chain: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(content), "\n"); n != 20 {
+		t.Errorf("got %d entries, want 20", n)
+	}
+}
+
+func TestAuditLogRecordsTheUserAndTheServingEndpoint(t *testing.T) {
+	cluster := newFakeCluster(t)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// A token login has no username, the user is read from the cluster.
+	cluster.mu.Lock()
+	cluster.tokens = append(cluster.tokens, "root-token")
+	cluster.mu.Unlock()
+	c, err := NewClient(context.Background(), Config{
+		Backend:      BackendNative,
+		Endpoints:    []string{closed.URL, cluster.URL},
+		AuthToken:    "root-token",
+		AuditLogPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateNamespace(context.Background(), &Namespace{Name: "ns1"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.audit.file.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry auditEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.User != "root" {
+		t.Errorf("got user %q, want root", entry.User)
+	}
+	if entry.Endpoint != cluster.URL {
+		t.Errorf("got endpoint %q, want %q which served the call", entry.Endpoint, cluster.URL)
+	}
+}
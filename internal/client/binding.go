@@ -0,0 +1,171 @@
+//go:build cgo
+
+package client
+
+import (
+	"context"
+
+	objectscale "github.com/vangork/objectscale-client/golang/pkg"
+)
+
+// bindingAPI is the backend calling the management API through the objectscale-client binding.
+// The binding makes its own requests: it logs in with the username and password,
+// verifies the endpoint against the system trust store unless insecure is set,
+// neither retries nor logs its requests, and sends every namespace attribute
+// whatever the release of the cluster.
+type bindingAPI struct {
+	client *objectscale.ManagementClient
+	// limiter is shared by every call of the data sources and resources.
+	limiter limiter
+}
+
+var _ ManagementAPI = &bindingAPI{}
+
+// newBindingAPI logs the binding in to the endpoint.
+func newBindingAPI(endpoint string, limiter limiter, config Config) (ManagementAPI, error) {
+	managementClient, err := objectscale.NewManagementClient(endpoint, config.Username, config.Password, config.Insecure)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bindingAPI{
+		client:  managementClient,
+		limiter: limiter,
+	}, nil
+}
+
+func (b *bindingAPI) CreateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error) {
+	return call(ctx, b.limiter, func() (*Namespace, error) {
+		return namespaceFromBinding(b.client.CreateNamespace(namespaceToBinding(namespace)))
+	})
+}
+
+func (b *bindingAPI) GetNamespace(ctx context.Context, id string) (*Namespace, error) {
+	return call(ctx, b.limiter, func() (*Namespace, error) {
+		return namespaceFromBinding(b.client.GetNamespace(id))
+	})
+}
+
+func (b *bindingAPI) UpdateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error) {
+	return call(ctx, b.limiter, func() (*Namespace, error) {
+		return namespaceFromBinding(b.client.UpdateNamespace(namespaceToBinding(namespace)))
+	})
+}
+
+func (b *bindingAPI) DeleteNamespace(ctx context.Context, id string) error {
+	_, err := call(ctx, b.limiter, func() (struct{}, error) {
+		return struct{}{}, classifyBindingError(b.client.DeleteNamespace(id))
+	})
+	return err
+}
+
+func (b *bindingAPI) ListNamespaces(ctx context.Context, name string) ([]Namespace, error) {
+	return call(ctx, b.limiter, func() ([]Namespace, error) {
+		namespaces, err := b.client.ListNamespaces(name)
+		if err != nil {
+			return nil, classifyBindingError(err)
+		}
+		result := make([]Namespace, 0, len(namespaces))
+		for i := range namespaces {
+			result = append(result, *convertNamespaceFromBinding(&namespaces[i]))
+		}
+		return result, nil
+	})
+}
+
+func namespaceFromBinding(namespace *objectscale.Namespace, err error) (*Namespace, error) {
+	if err != nil {
+		return nil, classifyBindingError(err)
+	}
+	return convertNamespaceFromBinding(namespace), nil
+}
+
+func convertNamespaceFromBinding(ns *objectscale.Namespace) *Namespace {
+	userMapping := make([]UserMapping, 0, len(ns.UserMapping))
+	for _, mapping := range ns.UserMapping {
+		attributes := make([]Attribute, 0, len(mapping.Attributes))
+		for _, attribute := range mapping.Attributes {
+			attributes = append(attributes, Attribute{Key: attribute.Key, Value: attribute.Value})
+		}
+		userMapping = append(userMapping, UserMapping{Domain: mapping.Domain, Attributes: attributes, Groups: mapping.Groups})
+	}
+	retentionClasses := make([]RetentionClass, 0, len(ns.RetentionClasses.RetentionClass))
+	for _, class := range ns.RetentionClasses.RetentionClass {
+		retentionClasses = append(retentionClasses, RetentionClass{Name: class.Name, Period: class.Period})
+	}
+
+	return &Namespace{
+		Name:                         ns.Name,
+		Id:                           ns.Id,
+		Global:                       ns.Global,
+		Remote:                       ns.Remote,
+		Link:                         Link{Rel: ns.Link.Rel, Href: ns.Link.Href},
+		CreationTime:                 ns.CreationTime,
+		Inactive:                     ns.Inactive,
+		Internal:                     ns.Internal,
+		DefaultDataServicesVpool:     ns.DefaultDataServicesVpool,
+		AllowedVpoolsList:            ns.AllowedVpoolsList,
+		DisallowedVpoolsList:         ns.DisallowedVpoolsList,
+		NamespaceAdmins:              ns.NamespaceAdmins,
+		UserMapping:                  userMapping,
+		IsEncryptionEnabled:          ns.IsEncryptionEnabled,
+		DefaultBucketBlockSize:       ns.DefaultBucketBlockSize,
+		ExternalGroupAdmins:          ns.ExternalGroupAdmins,
+		IsStaleAllowed:               ns.IsStaleAllowed,
+		IsObjectLockWithAdoAllowed:   ns.IsObjectLockWithAdoAllowed,
+		IsComplianceEnabled:          ns.IsComplianceEnabled,
+		NotificationSize:             ns.NotificationSize,
+		BlockSize:                    ns.BlockSize,
+		NotificationSizeInCount:      ns.NotificationSizeInCount,
+		BlockSizeInCount:             ns.BlockSizeInCount,
+		DefaultAuditDeleteExpiration: ns.DefaultAuditDeleteExpiration,
+		RetentionClasses:             RetentionClasses{RetentionClass: retentionClasses},
+		RootUserName:                 ns.RootUserName,
+		RootUserPassword:             ns.RootUserPassword,
+	}
+}
+
+func namespaceToBinding(ns *Namespace) *objectscale.Namespace {
+	userMapping := make([]objectscale.UserMapping, 0, len(ns.UserMapping))
+	for _, mapping := range ns.UserMapping {
+		attributes := make([]objectscale.Attribute, 0, len(mapping.Attributes))
+		for _, attribute := range mapping.Attributes {
+			attributes = append(attributes, objectscale.Attribute{Key: attribute.Key, Value: attribute.Value})
+		}
+		userMapping = append(userMapping, objectscale.UserMapping{Domain: mapping.Domain, Attributes: attributes, Groups: mapping.Groups})
+	}
+	retentionClasses := make([]objectscale.RetentionClass, 0, len(ns.RetentionClasses.RetentionClass))
+	for _, class := range ns.RetentionClasses.RetentionClass {
+		retentionClasses = append(retentionClasses, objectscale.RetentionClass{Name: class.Name, Period: class.Period})
+	}
+
+	return &objectscale.Namespace{
+		Name:                         ns.Name,
+		Id:                           ns.Id,
+		Global:                       ns.Global,
+		Remote:                       ns.Remote,
+		Link:                         objectscale.Link{Rel: ns.Link.Rel, Href: ns.Link.Href},
+		CreationTime:                 ns.CreationTime,
+		Inactive:                     ns.Inactive,
+		Internal:                     ns.Internal,
+		DefaultDataServicesVpool:     ns.DefaultDataServicesVpool,
+		AllowedVpoolsList:            ns.AllowedVpoolsList,
+		DisallowedVpoolsList:         ns.DisallowedVpoolsList,
+		NamespaceAdmins:              ns.NamespaceAdmins,
+		UserMapping:                  userMapping,
+		IsEncryptionEnabled:          ns.IsEncryptionEnabled,
+		DefaultBucketBlockSize:       ns.DefaultBucketBlockSize,
+		ExternalGroupAdmins:          ns.ExternalGroupAdmins,
+		IsStaleAllowed:               ns.IsStaleAllowed,
+		IsObjectLockWithAdoAllowed:   ns.IsObjectLockWithAdoAllowed,
+		IsComplianceEnabled:          ns.IsComplianceEnabled,
+		NotificationSize:             ns.NotificationSize,
+		BlockSize:                    ns.BlockSize,
+		NotificationSizeInCount:      ns.NotificationSizeInCount,
+		BlockSizeInCount:             ns.BlockSizeInCount,
+		DefaultAuditDeleteExpiration: ns.DefaultAuditDeleteExpiration,
+		RetentionClasses:             objectscale.RetentionClasses{RetentionClass: retentionClasses},
+		RootUserName:                 ns.RootUserName,
+		RootUserPassword:             ns.RootUserPassword,
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package client
+
+import (
+	"errors"
+)
+
+func newBindingAPI(endpoint string, limiter limiter, config Config) (ManagementAPI, error) {
+	return nil, errors.New("the binding backend is not available in a provider built without cgo, use the native backend")
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// namespaceCache answers the reads of namespaces from the namespaces read at the first
+// read. The list call only returns identifiers and names, so filling it still reads each
+// namespace: it runs the reads concurrently up front rather than saving any. It lives as
+// long as the provider, which Terraform configures once per run.
+type namespaceCache struct {
+	mu     sync.Mutex
+	filled bool
+	// failed disables the cache once the list call failed, reads then go to the cluster.
+	failed bool
+	// filling is closed when the list call in flight returns, nil when there is none.
+	// Concurrent reads wait for and share that call, without holding mu.
+	filling chan struct{}
+	// stale are the namespaces written while the list call is in flight.
+	stale []string
+	// namespaces are keyed by both identifier and name.
+	namespaces map[string]Namespace
+}
+
+func newNamespaceCache() *namespaceCache {
+	return &namespaceCache{namespaces: map[string]Namespace{}}
+}
+
+// get returns the cached namespace, filling the cache with list on first use.
+func (c *namespaceCache) get(ctx context.Context, id string, list func(ctx context.Context) ([]Namespace, error)) (*Namespace, bool) {
+	if !c.ready(ctx, list) {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	namespace, ok := c.namespaces[id]
+	if !ok {
+		return nil, false
+	}
+	return &namespace, true
+}
+
+// ready fills the cache unless it is filled or another read is filling it, in which
+// case it waits for that read. It tells whether the cache can answer.
+func (c *namespaceCache) ready(ctx context.Context, list func(ctx context.Context) ([]Namespace, error)) bool {
+	for {
+		c.mu.Lock()
+		if c.filled || c.failed {
+			filled := c.filled
+			c.mu.Unlock()
+			return filled
+		}
+		if filling := c.filling; filling != nil {
+			c.mu.Unlock()
+			select {
+			case <-filling:
+				continue
+			case <-ctx.Done():
+				return false
+			}
+		}
+		filling := make(chan struct{})
+		c.filling = filling
+		c.mu.Unlock()
+
+		namespaces, err := list(ctx)
+
+		c.mu.Lock()
+		c.filling = nil
+		close(filling)
+		switch {
+		case err == nil:
+			c.fillLocked(namespaces)
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			// Only this read gave up, the next one fills the cache.
+		default:
+			tflog.Warn(ctx, "unable to fill the namespace cache, reading namespaces one by one", map[string]interface{}{
+				"error": err.Error(),
+			})
+			c.failed = true
+		}
+		c.stale = nil
+		filled := c.filled
+		c.mu.Unlock()
+		return filled
+	}
+}
+
+// fill replaces the cached namespaces with the result of a list call.
+func (c *namespaceCache) fill(namespaces []Namespace) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fillLocked(namespaces)
+}
+
+func (c *namespaceCache) fillLocked(namespaces []Namespace) {
+	c.namespaces = make(map[string]Namespace, 2*len(namespaces))
+	for _, namespace := range namespaces {
+		c.namespaces[namespace.Id] = namespace
+		c.namespaces[namespace.Name] = namespace
+	}
+	c.filled = true
+	// The list call may have read them before they were written.
+	if c.filling == nil {
+		c.invalidateLocked(c.stale...)
+		c.stale = nil
+	}
+}
+
+// invalidate drops the namespace after a write, its next read goes to the cluster.
+func (c *namespaceCache) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.filling != nil {
+		c.stale = append(c.stale, keys...)
+	}
+	c.invalidateLocked(keys...)
+}
+
+func (c *namespaceCache) invalidateLocked(keys ...string) {
+	for _, key := range keys {
+		if namespace, ok := c.namespaces[key]; ok {
+			delete(c.namespaces, namespace.Id)
+			delete(c.namespaces, namespace.Name)
+		}
+	}
+}
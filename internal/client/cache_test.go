@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNamespaceCacheSharesOneListCall(t *testing.T) {
+	cache := newNamespaceCache()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	list := func(ctx context.Context) ([]Namespace, error) {
+		calls.Add(1)
+		<-release
+		return []Namespace{{Id: "ns1", Name: "ns1"}, {Id: "ns2", Name: "ns2"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	hits := make([]bool, 5)
+	for i := range hits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, hits[i] = cache.get(context.Background(), "ns1", list)
+		}()
+	}
+
+	// The fill does not hold the lock, a write is not blocked by it.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cache.invalidate("ns2")
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("%d list calls, want 1", calls.Load())
+	}
+	for i, hit := range hits {
+		if !hit {
+			t.Errorf("read %d missed the cache", i)
+		}
+	}
+	if _, ok := cache.get(context.Background(), "ns2", list); ok {
+		t.Error("the namespace written during the fill is still cached")
+	}
+}
+
+func TestNamespaceCacheFillFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		// wantRetry tells whether the next read fills the cache again.
+		wantRetry bool
+	}{
+		{name: "cluster failure", err: errors.New("forbidden")},
+		{name: "cancelled read", err: fmt.Errorf("management API call aborted: %w", context.Canceled), wantRetry: true},
+		{name: "read timed out", err: fmt.Errorf("GET /object/namespaces: %w", context.DeadlineExceeded), wantRetry: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newNamespaceCache()
+			var calls int
+			if _, ok := cache.get(context.Background(), "ns1", func(ctx context.Context) ([]Namespace, error) {
+				calls++
+				return nil, test.err
+			}); ok {
+				t.Fatal("got a namespace from a failed fill")
+			}
+
+			_, ok := cache.get(context.Background(), "ns1", func(ctx context.Context) ([]Namespace, error) {
+				calls++
+				return []Namespace{{Id: "ns1", Name: "ns1"}}, nil
+			})
+			if ok != test.wantRetry || (calls == 2) != test.wantRetry {
+				t.Errorf("got hit %v after %d list calls, want the fill retried %v", ok, calls, test.wantRetry)
+			}
+		})
+	}
+}
+
+func TestNamespaceCacheWaitHonorsTheContext(t *testing.T) {
+	cache := newNamespaceCache()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go cache.get(context.Background(), "ns1", func(ctx context.Context) ([]Namespace, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, ok := cache.get(ctx, "ns1", nil); ok {
+		t.Error("got a namespace while the fill is in flight")
+	}
+}
+
+func TestNativeListReadsTheNamespacesConcurrently(t *testing.T) {
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{MaxConcurrentRequests: 2, ReadCache: true})
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		cluster.namespaces[fmt.Sprintf("ns%d", i)] = Namespace{Id: fmt.Sprintf("ns%d", i), Name: fmt.Sprintf("ns%d", i)}
+	}
+
+	for i := 0; i < 20; i++ {
+		namespace, err := c.GetNamespace(ctx, fmt.Sprintf("ns%d", i))
+		if err != nil || namespace.Name != fmt.Sprintf("ns%d", i) {
+			t.Fatalf("GetNamespace(ns%d) = %v, %v", i, namespace, err)
+		}
+	}
+	if got := cluster.count("GET " + namespacesPath); got != 1 {
+		t.Errorf("%d list calls, want 1", got)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// limiter bounds the number of concurrent management API calls, nil means no bound.
+type limiter chan struct{}
+
+func newLimiter(size int) limiter {
+	if size <= 0 {
+		return nil
+	}
+	return make(limiter, size)
+}
+
+func (l limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for a free management API slot: %w", ctx.Err())
+	}
+}
+
+func (l limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// call runs a management API call, returning early when the context is done.
+//
+// The calls of the objectscale-client binding do not accept a context and cannot
+// be interrupted. An abandoned call keeps running in the background and holds its
+// limiter slot until it completes, its result is discarded.
+func call[T any](ctx context.Context, l limiter, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	var zero T
+	if err := l.acquire(ctx); err != nil {
+		return zero, err
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		defer l.release()
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, fmt.Errorf("management API call aborted: %w", ctx.Err())
+	}
+}
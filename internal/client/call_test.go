@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallBoundsTheConcurrentCalls(t *testing.T) {
+	l := newLimiter(2)
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = call(context.Background(), l, func() (struct{}, error) {
+				now := running.Add(1)
+				for {
+					old := peak.Load()
+					if now <= old || peak.CompareAndSwap(old, now) {
+						break
+					}
+				}
+				<-release
+				running.Add(-1)
+				return struct{}{}, nil
+			})
+		}()
+	}
+
+	// Let the calls pile up on the limiter before releasing them.
+	deadline := time.Now().Add(time.Second)
+	for running.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Errorf("got %d concurrent calls, want 2", got)
+	}
+}
+
+func TestCallUnbounded(t *testing.T) {
+	if l := newLimiter(0); l != nil {
+		t.Fatalf("newLimiter(0) = %v, want no bound", l)
+	}
+	value, err := call(context.Background(), newLimiter(0), func() (int, error) { return 42, nil })
+	if err != nil || value != 42 {
+		t.Errorf("got %d, %v, want 42", value, err)
+	}
+}
+
+func TestAbandonedCallKeepsItsSlot(t *testing.T) {
+	l := newLimiter(1)
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := call(ctx, l, func() (struct{}, error) {
+		defer close(finished)
+		close(started)
+		<-release
+		return struct{}{}, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the call aborted by its context", err)
+	}
+
+	// The abandoned call still runs, the next one cannot get a slot.
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+	if _, err := call(waitCtx, l, func() (struct{}, error) { return struct{}{}, nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want no free slot while the abandoned call runs", err)
+	}
+
+	close(release)
+	<-finished
+	value, err := call(context.Background(), l, func() (string, error) { return "done", nil })
+	if err != nil || value != "done" {
+		t.Errorf("got %q, %v, want the slot back once the abandoned call completed", value, err)
+	}
+}
+
+func TestLimiterAcquireIsCancelledByTheContext(t *testing.T) {
+	l := newLimiter(1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer l.release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.acquire(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want the acquire cancelled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("acquire did not return after its context was cancelled")
+	}
+}
@@ -1,27 +1,285 @@
 package client
 
 import (
-	objectscale "github.com/vangork/objectscale-client/golang/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// ErrReadOnly is returned by the calls changing the cluster when the provider is read-only.
+var ErrReadOnly = errors.New("the provider is configured as read-only, changes to the cluster are refused")
+
+// Client is the ManagementAPI given to the data sources and resources. It decorates
+// the backend with the read-only mode and the protection of namespaces.
 type Client struct {
-	ManagementClient *objectscale.ManagementClient
+	// api is the backend making the calls.
+	api ManagementAPI
+	// requestIDs is set when the backend sends the request ID of the calls, which the
+	// binding cannot.
+	requestIDs bool
+	// readOnly refuses every call changing the cluster.
+	readOnly bool
+	// protection refuses to delete the protected namespaces.
+	protection namespaceProtection
+	// version is the release of the cluster, used to reject unsupported attributes.
+	version Version
+	// cache answers the reads of namespaces when the read cache is enabled, nil otherwise.
+	cache *namespaceCache
+	// audit records the calls changing the cluster when the audit log is enabled, nil otherwise.
+	// It is shared with the clients of the other endpoints.
+	audit *auditLog
+	// auditUser is the user the calls are recorded for, the one logged in or owning the token.
+	auditUser string
+	// limiter bounds the concurrent calls, it is shared with the clients of the other endpoints.
+	limiter limiter
+
+	// endpoint is the primary endpoint, the calls are addressed to.
+	endpoint string
+	// config and logCtx create the clients of the other endpoints.
+	config Config
+	logCtx context.Context
+	// endpointsMu guards endpointClients, the clients of the other endpoints by endpoint.
+	// It is not held during their login.
+	endpointsMu     sync.Mutex
+	endpointClients map[string]*endpointClient
+}
+
+// Config holds the settings used to connect to the management API.
+type Config struct {
+	// Backend is the implementation of the calls, BackendBinding or BackendNative.
+	// The native backend is the default.
+	Backend string
+	// Endpoints are the management endpoints, tried according to EndpointSelection.
+	Endpoints         []string
+	EndpointSelection string
+
+	Username string
+	Password string
+	Insecure bool
+	// CACertificate is a PEM bundle of the CAs trusted to sign the endpoint certificate.
+	CACertificate string
+	// ClientCertificate and ClientKey are the PEM encoded pair presented for mutual TLS.
+	ClientCertificate string
+	ClientKey         string
+	// AuthToken is an existing X-SDS-AUTH-TOKEN used instead of logging in.
+	AuthToken string
+	// TokenCacheFile is the file where session tokens are reused across runs, disabled when empty.
+	TokenCacheFile string
+	// ProxyURL is the proxy of the management API traffic, HTTPS_PROXY is used when empty.
+	ProxyURL string
+	// Retry controls how transient failures are retried.
+	Retry RetryConfig
+	// MaxConcurrentRequests bounds the concurrent management API calls, 0 means no bound.
+	MaxConcurrentRequests int
+	// ReadOnly refuses every call changing the cluster.
+	ReadOnly bool
+	// ProtectedNamespaces are the names or glob patterns of the namespaces that must not be deleted.
+	ProtectedNamespaces []string
+	// ProtectedNamespacesOverride are the names of protected namespaces whose deletion is allowed anyway.
+	ProtectedNamespacesOverride []string
+	// ReadCache reads all the namespaces at the first read, one list call plus one read per
+	// namespace, and answers the reads of namespaces from them for the life of the client.
+	ReadCache bool
+	// AuditLogPath is the file where every call changing the cluster is recorded, disabled when empty.
+	AuditLogPath string
 }
 
-func NewClient(
-	endpoint string,
-	username string,
-	password string,
-	insecure bool,
-) (*Client, error) {
-	managementClient, err := objectscale.NewManagementClient(endpoint, username, password, insecure)
+// NewClient logs in to the management API.
+// The context is only used for logging, including by the calls made later on.
+func NewClient(ctx context.Context, config Config) (*Client, error) {
+	var audit *auditLog
+	if config.AuditLogPath != "" {
+		var err error
+		if audit, err = openAuditLog(config.AuditLogPath); err != nil {
+			return nil, err
+		}
+	}
+	client, err := newClient(ctx, config, newLimiter(config.MaxConcurrentRequests), audit)
+	if err != nil && audit != nil {
+		_ = audit.file.Close()
+	}
+	return client, err
+}
+
+// newClient logs in to the management API, bounding the calls with the given limiter and
+// recording them in the given audit log, if any.
+func newClient(ctx context.Context, config Config, limiter limiter, audit *auditLog) (*Client, error) {
+	backend := config.Backend
+	if backend == "" {
+		backend = defaultBackend
+	}
+	if backend == BackendBinding {
+		if err := checkBindingConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := newProxyFunc(config.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.TLSClientConfig = tlsConfig
+	httpTransport.Proxy = proxy
+	httpTransport.OnProxyConnectResponse = checkProxyConnect
+	logging := &loggingTransport{
+		base:   httpTransport,
+		logCtx: newHTTPLogContext(ctx),
+	}
+	pool, err := newPoolTransport(ctx, logging, config.Endpoints, config.EndpointSelection)
 	if err != nil {
 		return nil, err
 	}
+	transport := &retryTransport{
+		base:   pool,
+		config: config.Retry,
+		logCtx: ctx,
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	// Requests are addressed to the primary endpoint, the pool picks the one serving them.
+	endpoint := pool.primary()
+	session, err := newClientSession(config, endpoint, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Log in before the backend does, so failures keep their type.
+	if _, err := session.current(); err != nil {
+		return nil, err
+	}
+
+	// apiClient sends the calls of the provider itself with the session token.
+	apiClient := &http.Client{Transport: &sessionTransport{base: transport, session: session}}
+
+	version, versionErr := detectVersion(apiClient, endpoint)
+	if versionErr != nil {
+		// Listing the nodes needs a monitoring role, the attributes are then not checked.
+		tflog.Warn(ctx, "unable to detect the objectscale version", map[string]interface{}{
+			"error": versionErr.Error(),
+		})
+	} else {
+		tflog.Debug(ctx, "detected the objectscale version", map[string]interface{}{
+			"version": version.String(),
+		})
+	}
+
+	var api ManagementAPI
+	switch backend {
+	case BackendBinding:
+		api, err = newBindingAPI(endpoint, limiter, config)
+	case BackendNative:
+		api = newNativeAPI(endpoint, apiClient, limiter, version)
+	default:
+		err = fmt.Errorf("unknown backend %q", backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tflog.Debug(ctx, "using the objectscale backend", map[string]interface{}{
+		"backend": backend,
+	})
 
 	client := Client{
-		ManagementClient: managementClient,
+		api:        api,
+		requestIDs: backend == BackendNative,
+		readOnly:   config.ReadOnly,
+		protection: newNamespaceProtection(config.ProtectedNamespaces, config.ProtectedNamespacesOverride),
+		version:    version,
+		limiter:    limiter,
+		endpoint:   endpoint,
+		config:     config,
+		logCtx:     ctx,
+	}
+	if config.ReadCache {
+		client.cache = newNamespaceCache()
+	}
+	if audit != nil {
+		client.audit = audit
+		client.auditUser = config.Username
+		// A token, from auth_token or a credential process, may belong to another user.
+		if config.AuthToken != "" || client.auditUser == "" {
+			if client.auditUser, err = whoami(apiClient, endpoint); err != nil {
+				return nil, fmt.Errorf("unable to identify the user recorded in the audit log: %w", err)
+			}
+		}
 	}
 
 	return &client, nil
 }
+
+// checkBindingConfig refuses the settings the binding backend cannot honour, as
+// it makes its own requests to the endpoint.
+func checkBindingConfig(config Config) error {
+	var unsupported []string
+	if config.CACertificate != "" {
+		unsupported = append(unsupported, "a CA certificate")
+	}
+	if config.ClientCertificate != "" {
+		unsupported = append(unsupported, "a client certificate")
+	}
+	if config.AuthToken != "" {
+		unsupported = append(unsupported, "an auth token")
+	}
+	if config.TokenCacheFile != "" {
+		unsupported = append(unsupported, "a token cache file")
+	}
+	if config.ProxyURL != "" {
+		unsupported = append(unsupported, "a proxy URL")
+	}
+	if len(config.Endpoints) > 1 {
+		unsupported = append(unsupported, "several endpoints")
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("the binding backend does not support %s, use the native backend", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// newClientSession creates the session, seeded with the configured auth token
+// or a still valid token from the cache.
+func newClientSession(config Config, endpoint string, httpClient *http.Client) (*session, error) {
+	session := newSession(endpoint, config.Username, config.Password, httpClient)
+
+	if config.AuthToken != "" {
+		valid, err := checkToken(httpClient, endpoint, config.AuthToken)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, fmt.Errorf("the auth token was rejected by %s, it may have expired", endpoint)
+		}
+		session.token = config.AuthToken
+		return session, nil
+	}
+
+	if config.TokenCacheFile == "" {
+		return session, nil
+	}
+
+	cache := newTokenCache(config.TokenCacheFile, endpoint, config.Username)
+	if cached, ok := cache.load(); ok {
+		// A cached token can still have been revoked or timed out on the cluster.
+		if valid, err := checkToken(httpClient, endpoint, cached); err == nil && valid {
+			session.token = cached
+		} else {
+			_ = cache.remove()
+		}
+	}
+	session.onLogin = func(token string) {
+		_ = cache.store(token)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBindingRefusesTheNativeOnlySettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "CA certificate", config: Config{CACertificate: "pem"}, want: "a CA certificate"},
+		{name: "client certificate", config: Config{ClientCertificate: "pem", ClientKey: "key"}, want: "a client certificate"},
+		{name: "auth token", config: Config{AuthToken: "token"}, want: "an auth token"},
+		{name: "token cache", config: Config{TokenCacheFile: "tokens.json"}, want: "a token cache file"},
+		{name: "proxy", config: Config{ProxyURL: "http://proxy:3128"}, want: "a proxy URL"},
+		{name: "several endpoints", config: Config{Endpoints: []string{"https://a:4443", "https://b:4443"}}, want: "several endpoints"},
+		{name: "several settings", config: Config{AuthToken: "token", ProxyURL: "http://proxy:3128"}, want: "an auth token, a proxy URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Backend = BackendBinding
+			if len(config.Endpoints) == 0 {
+				config.Endpoints = []string{"https://a:4443"}
+			}
+			// The settings are refused before any request is sent.
+			_, err := NewClient(context.Background(), config)
+			if err == nil || !strings.Contains(err.Error(), "does not support "+tt.want+", use the native backend") {
+				t.Fatalf("got %v, want the refusal of %s", err, tt.want)
+			}
+		})
+	}
+
+	if err := checkBindingConfig(Config{Endpoints: []string{"https://a:4443"}, Username: "root", Password: "password", Insecure: true}); err != nil {
+		t.Errorf("got %v for the settings the binding supports", err)
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialProcessTimeout bounds a credential process, which may prompt a vault or an agent.
+const credentialProcessTimeout = time.Minute
+
+// Credentials are the login details printed by a credential process, as a JSON document
+// with either a username and a password or a token.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// credentialProcesses caches the credentials of every command for the life of the
+// provider process, so the command runs once however often the provider is configured.
+var credentialProcesses = struct {
+	sync.Mutex
+	results map[string]Credentials
+}{results: map[string]Credentials{}}
+
+// RunCredentialProcess runs the command, without a shell, and parses the credentials it prints.
+func RunCredentialProcess(ctx context.Context, command string) (Credentials, error) {
+	credentialProcesses.Lock()
+	defer credentialProcesses.Unlock()
+	if credentials, ok := credentialProcesses.results[command]; ok {
+		return credentials, nil
+	}
+
+	args, err := splitCommand(command)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if len(args) == 0 {
+		return Credentials{}, errors.New("the credential process command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return Credentials{}, fmt.Errorf("the credential process %q failed: %w: %s", args[0], err, message)
+		}
+		return Credentials{}, fmt.Errorf("the credential process %q failed: %w", args[0], err)
+	}
+
+	// The output is not part of the errors, it holds secrets.
+	var credentials Credentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("the credential process %q printed an invalid JSON document", args[0])
+	}
+	if credentials.Token == "" && (credentials.Username == "" || credentials.Password == "") {
+		return Credentials{}, fmt.Errorf("the credential process %q printed neither a token nor a username and a password", args[0])
+	}
+
+	credentialProcesses.results[command] = credentials
+	return credentials, nil
+}
+
+// splitCommand splits a command line on spaces, keeping the quoted arguments together.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var quote rune
+	inArg := false
+	escaped := false
+	for _, r := range command {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in the credential process command")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
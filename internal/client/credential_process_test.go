@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command   string
+		want      []string
+		wantError bool
+	}{
+		{command: "vault-objectscale --cluster prod", want: []string{"vault-objectscale", "--cluster", "prod"}},
+		{command: "  spaced \t out\n ", want: []string{"spaced", "out"}},
+		{command: `tool "two words" 'single quoted'`, want: []string{"tool", "two words", "single quoted"}},
+		{command: `tool "it's" 'say "hi"'`, want: []string{"tool", "it's", `say "hi"`}},
+		{command: `tool two\ words \"quoted\"`, want: []string{"tool", "two words", `"quoted"`}},
+		{command: `tool "a \"b\" c"`, want: []string{"tool", `a "b" c`}},
+		{command: `tool 'C:\path'`, want: []string{"tool", `C:\path`}},
+		{command: `tool "" ''`, want: []string{"tool", "", ""}},
+		{command: `tool pre"fix"ed`, want: []string{"tool", "prefixed"}},
+		{command: "", want: nil},
+		{command: "   ", want: nil},
+		{command: `tool "unterminated`, wantError: true},
+		{command: `tool 'unterminated`, wantError: true},
+		{command: `tool trailing\`, wantError: true},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.command)
+		if (err != nil) != tt.wantError {
+			t.Errorf("splitCommand(%q) error = %v, want error %v", tt.command, err, tt.wantError)
+			continue
+		}
+		if !tt.wantError && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+// TestCredentialProcessHelper is the credential process run by TestRunCredentialProcess.
+// It appends a line to the file given as its first argument, then prints its last
+// argument, or writes it to stderr and fails when the one before is "fail".
+func TestCredentialProcessHelper(t *testing.T) {
+	if os.Getenv("OBJECTSCALE_TEST_CREDENTIAL_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if file, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); err == nil {
+		_, _ = file.WriteString("run\n")
+		file.Close()
+	}
+	if len(args) == 3 && args[1] == "fail" {
+		fmt.Fprint(os.Stderr, args[2])
+		os.Exit(3)
+	}
+	fmt.Print(args[len(args)-1])
+	os.Exit(0)
+}
+
+// helperCommand returns the command line running TestCredentialProcessHelper with the
+// arguments, and the file counting its runs. The file makes the command unique, the
+// results are cached by command.
+func helperCommand(t *testing.T, args ...string) (string, string) {
+	t.Helper()
+	t.Setenv("OBJECTSCALE_TEST_CREDENTIAL_PROCESS", "1")
+	runs := filepath.Join(t.TempDir(), "runs")
+	quoted := []string{"'" + os.Args[0] + "'", "-test.run=^TestCredentialProcessHelper$", "--", "'" + runs + "'"}
+	for _, arg := range args {
+		quoted = append(quoted, "'"+arg+"'")
+	}
+	return strings.Join(quoted, " "), runs
+}
+
+// countRuns returns the number of runs of the helper recorded in the file.
+func countRuns(t *testing.T, runs string) int {
+	t.Helper()
+	data, err := os.ReadFile(runs)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "run\n")
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      Credentials
+		wantError string
+	}{
+		{name: "username and password", args: []string{`{"username":"root","password":"secret"}`}, want: Credentials{Username: "root", Password: "secret"}},
+		{name: "token", args: []string{`{"token":"t1"}`}, want: Credentials{Token: "t1"}},
+		{name: "unknown fields", args: []string{`{"token":"t1","expiration":"2030-01-01T00:00:00Z"}`}, want: Credentials{Token: "t1"}},
+		{name: "invalid JSON", args: []string{`{"password":"secret"`}, wantError: "printed an invalid JSON document"},
+		{name: "not an object", args: []string{`"secret"`}, wantError: "printed an invalid JSON document"},
+		{name: "username without password", args: []string{`{"username":"root"}`}, wantError: "printed neither a token nor a username and a password"},
+		{name: "password without username", args: []string{`{"password":"secret"}`}, wantError: "printed neither a token nor a username and a password"},
+		{name: "empty document", args: []string{`{}`}, wantError: "printed neither a token nor a username and a password"},
+		{name: "failure", args: []string{"fail", "vault is sealed"}, wantError: "vault is sealed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, runs := helperCommand(t, tt.args...)
+			got, err := RunCredentialProcess(context.Background(), command)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got %v, want an error containing %q", err, tt.wantError)
+				}
+				if strings.Contains(err.Error(), "secret") {
+					t.Errorf("the error %q holds the output of the process", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != tt.want {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+			}
+
+			// Only successes are cached, a failed process runs again.
+			_, _ = RunCredentialProcess(context.Background(), command)
+			wantRuns := 1
+			if tt.wantError != "" {
+				wantRuns = 2
+			}
+			if n := countRuns(t, runs); n != wantRuns {
+				t.Errorf("the process ran %d times, want %d", n, wantRuns)
+			}
+		})
+	}
+}
+
+func TestRunCredentialProcessCommandErrors(t *testing.T) {
+	for command, want := range map[string]string{
+		"":                       "the credential process command is empty",
+		`"unterminated`:          "unterminated quote or escape",
+		"objectscale-missing-cp": `the credential process "objectscale-missing-cp" failed`,
+	} {
+		if _, err := RunCredentialProcess(context.Background(), command); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("RunCredentialProcess(%q) = %v, want an error containing %q", command, err, want)
+		}
+	}
+}
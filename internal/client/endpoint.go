@@ -0,0 +1,76 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ForEndpoint returns the client pinned to an endpoint, created and logged in with the
+// provider settings on first use, then reused for the life of the client, as is a failure
+// to create it. The client itself is returned for an empty endpoint, or for its endpoint
+// when it has only one: with several, its calls may be served by any of them.
+func (c *Client) ForEndpoint(endpoint string) (ManagementAPI, error) {
+	key, err := endpointKey(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return c, nil
+	}
+	if len(c.config.Endpoints) == 1 {
+		if configuredKey, err := endpointKey(c.config.Endpoints[0]); err == nil && configuredKey == key {
+			return c, nil
+		}
+	}
+
+	c.endpointsMu.Lock()
+	entry, ok := c.endpointClients[key]
+	if !ok {
+		entry = &endpointClient{}
+		if c.endpointClients == nil {
+			c.endpointClients = map[string]*endpointClient{}
+		}
+		c.endpointClients[key] = entry
+	}
+	c.endpointsMu.Unlock()
+
+	// Only the resources of the endpoint wait for its login, and share its outcome.
+	entry.once.Do(func() {
+		config := c.config
+		config.Endpoints = []string{endpoint}
+		config.EndpointSelection = ""
+		// The entries of every endpoint are chained in the one audit log, and the calls
+		// to every endpoint count against the one bound.
+		entry.client, entry.err = newClient(c.logCtx, config, c.limiter, c.audit)
+	})
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.client, nil
+}
+
+// endpointClient is the client of an endpoint, or the failure to create it, which is
+// kept for the life of the client rather than logging in again for every resource.
+type endpointClient struct {
+	once   sync.Once
+	client *Client
+	err    error
+}
+
+// endpointKey normalizes an endpoint to compare it, ex. with a trailing slash or in upper case.
+func endpointKey(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", nil
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
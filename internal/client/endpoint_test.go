@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEndpoint(t *testing.T) {
+	first, second := newFakeCluster(t), newFakeCluster(t)
+	first.namespaces["ns1"] = Namespace{Id: "ns1", Name: "ns1"}
+	second.namespaces["ns1"] = Namespace{Id: "ns1", Name: "ns1"}
+
+	c, err := NewClient(context.Background(), Config{
+		Backend:               BackendNative,
+		Endpoints:             []string{first.URL, second.URL},
+		Username:              "root",
+		Password:              "password",
+		MaxConcurrentRequests: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if api, err := c.ForEndpoint(" "); err != nil || api != c {
+		t.Errorf("ForEndpoint() = %v, %v, want the client itself", api, err)
+	}
+	if _, err := c.ForEndpoint("vdc2:4443"); err == nil {
+		t.Error("ForEndpoint accepted an endpoint without a scheme")
+	}
+
+	// An override is pinned to its endpoint, even a configured one, and shares the bound.
+	for _, cluster := range []*fakeCluster{first, second} {
+		api, err := c.ForEndpoint(cluster.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pinned, ok := api.(*Client)
+		if !ok || pinned == c {
+			t.Fatalf("ForEndpoint(%s) = %v, want a client of the endpoint", cluster.URL, api)
+		}
+		if pinned.limiter != c.limiter || pinned.api.(*nativeAPI).limiter != c.limiter {
+			t.Errorf("ForEndpoint(%s) has its own limiter", cluster.URL)
+		}
+		if again, _ := c.ForEndpoint(cluster.URL); again != api {
+			t.Errorf("ForEndpoint(%s) created a second client", cluster.URL)
+		}
+
+		before := cluster.count("GET " + namespacePath("ns1"))
+		for i := 0; i < 3; i++ {
+			if _, err := api.GetNamespace(context.Background(), "ns1"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := cluster.count("GET "+namespacePath("ns1")) - before; got != 3 {
+			t.Errorf("%s served %d of the 3 reads", cluster.URL, got)
+		}
+	}
+}
+
+func TestForEndpointOfASingleEndpoint(t *testing.T) {
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{})
+
+	api, err := c.ForEndpoint(cluster.URL + "/")
+	if err != nil || api != c {
+		t.Errorf("ForEndpoint(%s) = %v, %v, want the client itself", cluster.URL, api, err)
+	}
+}
+
+func TestForEndpointLogsInOncePerEndpoint(t *testing.T) {
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{})
+
+	// The login of a slow endpoint blocks its resources only.
+	var slowLogins atomic.Int32
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowLogins.Add(1)
+		<-release
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	// An endpoint refusing the login fails its resources, without another attempt.
+	var refusedLogins atomic.Int32
+	refused := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refusedLogins.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer refused.Close()
+
+	go func() { _, _ = c.ForEndpoint(slow.URL) }()
+	deadline := time.Now().Add(time.Second)
+	for slowLogins.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			if _, err := c.ForEndpoint(refused.URL); err == nil {
+				t.Error("ForEndpoint accepted an endpoint refusing the login")
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ForEndpoint waited for the login of another endpoint")
+	}
+	if n := refusedLogins.Load(); n != 1 {
+		t.Errorf("%d logins to the refusing endpoint, want 1", n)
+	}
+}
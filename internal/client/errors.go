@@ -0,0 +1,150 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind classifies the failures reported by the management API.
+type ErrorKind string
+
+const (
+	ErrorKindNotFound     ErrorKind = "not found"
+	ErrorKindConflict     ErrorKind = "conflict"
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	ErrorKindForbidden    ErrorKind = "forbidden"
+	ErrorKindValidation   ErrorKind = "validation"
+	ErrorKindThrottled    ErrorKind = "throttled"
+	ErrorKindUnavailable  ErrorKind = "unavailable"
+	ErrorKindUnknown      ErrorKind = "unknown"
+)
+
+// The ECS error codes whose HTTP status does not tell the kind of failure.
+const (
+	ecsCodeNotFound      = 1004
+	ecsCodeAlreadyExists = 1013
+)
+
+// APIError is a failure reported by the management API, as returned by both backends.
+type APIError struct {
+	// StatusCode is the HTTP status of the response, 0 when the backend did not report it.
+	StatusCode int
+	// Code is the ECS error code, ex. 1004, 0 when the response had none.
+	Code        int
+	Description string
+	Details     string
+	// Retryable is set when the cluster reports the call may succeed later.
+	Retryable bool
+	// RequestID is the identifier sent with the failed request, ServerRequestID
+	// the one given by the cluster, if any.
+	RequestID       string
+	ServerRequestID string
+}
+
+func (e *APIError) Error() string {
+	message := e.Description
+	if e.Details != "" && e.Details != e.Description {
+		message += ": " + e.Details
+	}
+	switch {
+	case e.StatusCode != 0 && e.Code != 0:
+		return fmt.Sprintf("%s (HTTP %d, error code %d)", message, e.StatusCode, e.Code)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("%s (HTTP %d)", message, e.StatusCode)
+	case e.Code != 0:
+		return fmt.Sprintf("%s (error code %d)", message, e.Code)
+	}
+	return message
+}
+
+// Kind classifies the failure from the error code first, then from the HTTP status.
+func (e *APIError) Kind() ErrorKind {
+	switch e.Code {
+	case ecsCodeNotFound:
+		return ErrorKindNotFound
+	case ecsCodeAlreadyExists:
+		return ErrorKindConflict
+	}
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrorKindConflict
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrorKindUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrorKindForbidden
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrorKindValidation
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrorKindThrottled
+	case e.StatusCode >= 500:
+		return ErrorKindUnavailable
+	}
+	return ErrorKindUnknown
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsNotFound reports whether err is the management API reporting a missing object.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Kind() == ErrorKindNotFound
+}
+
+// ecsError is the error document of the management API.
+type ecsError struct {
+	Code        int    `json:"code"`
+	Retryable   bool   `json:"retryable"`
+	Description string `json:"description"`
+	Details     string `json:"details"`
+}
+
+// newAPIError parses the error document of a failed response, the status
+// text stands in for the description when the document is missing.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Retryable:  statusCode == http.StatusTooManyRequests || statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable || statusCode == http.StatusGatewayTimeout,
+	}
+	var document ecsError
+	if err := json.Unmarshal(body, &document); err == nil && document.Description != "" {
+		apiErr.Code = document.Code
+		apiErr.Description = document.Description
+		apiErr.Details = document.Details
+		apiErr.Retryable = apiErr.Retryable || document.Retryable
+	} else {
+		apiErr.Description = strings.TrimSpace(http.StatusText(statusCode))
+	}
+	return apiErr
+}
+
+// classifyBindingError turns the error of the binding into an APIError when its
+// message embeds the error document of the management API. The binding reports
+// neither the HTTP status nor the headers, the kind is then only known from the
+// error code, and the error carries no request ID.
+func classifyBindingError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	start := strings.Index(message, "{")
+	end := strings.LastIndex(message, "}")
+	if start < 0 || end < start {
+		return err
+	}
+	body := []byte(message[start : end+1])
+	var document ecsError
+	if json.Unmarshal(body, &document) != nil || document.Description == "" {
+		return err
+	}
+	return newAPIError(0, body)
+}
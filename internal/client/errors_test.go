@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestBindingErrorKind parses the error documents the way a binding error carries them,
+// without the HTTP status of the response.
+func TestBindingErrorKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantKind  ErrorKind
+		wantCode  int
+		retryable bool
+	}{
+		{name: "not found code", body: `{"code":1004,"description":"Unable to find entity"}`, wantKind: ErrorKindNotFound, wantCode: 1004},
+		{name: "already exists code", body: `{"code":1013,"description":"Namespace already exists"}`, wantKind: ErrorKindConflict, wantCode: 1013},
+		{name: "other code", body: `{"code":3000,"description":"Insufficient permissions"}`, wantKind: ErrorKindUnknown, wantCode: 3000},
+		{name: "retryable", body: `{"code":6000,"description":"Too many requests","retryable":true}`, wantKind: ErrorKindUnknown, wantCode: 6000, retryable: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := classifyBindingError(fmt.Errorf("request failed: %s", test.body))
+			apiErr, ok := AsAPIError(err)
+			if !ok {
+				t.Fatalf("got %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != 0 || apiErr.Kind() != test.wantKind || apiErr.Code != test.wantCode || apiErr.Retryable != test.retryable {
+				t.Errorf("got status %d, kind %q, code %d, retryable %v", apiErr.StatusCode, apiErr.Kind(), apiErr.Code, apiErr.Retryable)
+			}
+			if sent, server := RequestIDs(err); sent != "" || server != "" {
+				t.Errorf("got request IDs %q and %q, want none", sent, server)
+			}
+		})
+	}
+}
+
+func TestClassifyBindingErrorWithoutDocument(t *testing.T) {
+	for _, err := range []error{
+		errors.New("connection refused"),
+		errors.New(`unexpected {"value":1}`),
+		errors.New("unbalanced } {"),
+	} {
+		if got := classifyBindingError(err); got != err {
+			t.Errorf("classifyBindingError(%q) = %v, want the error unchanged", err, got)
+		}
+	}
+}
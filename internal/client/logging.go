@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// HTTPLogSubsystem is the tflog subsystem tracing the management API traffic.
+// Its level can be set apart with the TF_LOG_PROVIDER_OBJECTSCALE_HTTP environment variable.
+const HTTPLogSubsystem = "objectscale_http"
+
+// maxLoggedBody is the size above which logged bodies are truncated.
+const maxLoggedBody = 64 * 1024
+
+// newHTTPLogContext returns a context logging to the HTTP subsystem.
+func newHTTPLogContext(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(ctx, HTTPLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_OBJECTSCALE", "http"))
+}
+
+// loggingTransport traces every request sent to the cluster, with its secrets redacted.
+type loggingTransport struct {
+	base http.RoundTripper
+	// logCtx carries the provider logger, it is never used to cancel requests.
+	logCtx context.Context
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields := map[string]interface{}{
+		"method":          req.Method,
+		"url":             redactURL(req.URL),
+		"request_headers": redactHeaders(req.Header),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		fields["request_body"] = loggedBody(body)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["latency"] = time.Since(start).String()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(t.logCtx, HTTPLogSubsystem, "management API request failed", fields)
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	fields["response_headers"] = redactHeaders(resp.Header)
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(t.logCtx, HTTPLogSubsystem, "management API request failed", fields)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fields["response_body"] = loggedBody(body)
+	tflog.SubsystemTrace(t.logCtx, HTTPLogSubsystem, "management API request", fields)
+
+	return resp, nil
+}
+
+func loggedBody(body []byte) string {
+	logged := redactBody(body)
+	if len(logged) > maxLoggedBody {
+		return logged[:maxLoggedBody] + "...(truncated)"
+	}
+	return logged
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+)
+
+// CreateNamespace creates a namespace.
+func (c *Client) CreateNamespace(ctx context.Context, namespace *Namespace) (result *Namespace, err error) {
+	ctx = withServedEndpoint(c.withRequestID(ctx))
+	ctx, span := startSpan(ctx, "CreateNamespace", namespace.Name)
+	defer func() { endSpan(span, err) }()
+	defer func() { c.record(ctx, "create", namespace.Name, namespace, err) }()
+
+	if c.readOnly {
+		return nil, ErrReadOnly
+	}
+	defer c.invalidateNamespace(namespace.Name)
+	return c.api.CreateNamespace(ctx, namespace)
+}
+
+// GetNamespace returns the namespace with the given identifier.
+func (c *Client) GetNamespace(ctx context.Context, id string) (result *Namespace, err error) {
+	ctx = withIdempotent(c.withRequestID(ctx))
+	ctx, span := startSpan(ctx, "GetNamespace", id)
+	defer func() { endSpan(span, err) }()
+
+	if c.cache != nil {
+		namespace, ok := c.cache.get(ctx, id, func(ctx context.Context) ([]Namespace, error) {
+			return c.api.ListNamespaces(c.withRequestID(ctx), "")
+		})
+		span.SetAttributes(AttributeCacheHit.Bool(ok))
+		if ok {
+			return namespace, nil
+		}
+	}
+	return c.api.GetNamespace(ctx, id)
+}
+
+// UpdateNamespace updates the namespace identified by namespace.Id.
+func (c *Client) UpdateNamespace(ctx context.Context, namespace *Namespace) (result *Namespace, err error) {
+	ctx = withServedEndpoint(withIdempotent(c.withRequestID(ctx)))
+	ctx, span := startSpan(ctx, "UpdateNamespace", namespace.Name)
+	defer func() { endSpan(span, err) }()
+	defer func() { c.record(ctx, "update", namespace.Id, namespace, err) }()
+
+	if c.readOnly {
+		return nil, ErrReadOnly
+	}
+	defer c.invalidateNamespace(namespace.Id, namespace.Name)
+	return c.api.UpdateNamespace(ctx, namespace)
+}
+
+// DeleteNamespace deletes the namespace with the given identifier, which is also its name.
+func (c *Client) DeleteNamespace(ctx context.Context, id string) (err error) {
+	// Deactivating a namespace again is harmless, the call is retried although it is a POST.
+	ctx = withServedEndpoint(withIdempotent(c.withRequestID(ctx)))
+	ctx, span := startSpan(ctx, "DeleteNamespace", id)
+	defer func() { endSpan(span, err) }()
+	defer func() { c.record(ctx, "delete", id, nil, err) }()
+
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	if err := c.CheckNamespaceDeletion(id); err != nil {
+		return err
+	}
+	defer c.invalidateNamespace(id)
+	return c.api.DeleteNamespace(ctx, id)
+}
+
+// ListNamespaces returns the namespaces matching the name, all of them when it is empty.
+func (c *Client) ListNamespaces(ctx context.Context, name string) (result []Namespace, err error) {
+	ctx = withIdempotent(c.withRequestID(ctx))
+	ctx, span := startSpan(ctx, "ListNamespaces", name)
+	defer func() { endSpan(span, err) }()
+
+	namespaces, err := c.api.ListNamespaces(ctx, name)
+	if err == nil && name == "" && c.cache != nil {
+		c.cache.fill(namespaces)
+	}
+	return namespaces, err
+}
+
+// invalidateNamespace drops a written namespace from the read cache, if enabled.
+func (c *Client) invalidateNamespace(keys ...string) {
+	if c.cache != nil {
+		c.cache.invalidate(keys...)
+	}
+}
+
+// record appends a call changing the cluster to the audit log, if enabled. The call is
+// recorded against the endpoint that served it, the primary one when none did.
+func (c *Client) record(ctx context.Context, operation, objectID string, request interface{}, err error) {
+	if c.audit == nil {
+		return
+	}
+	endpoint := contextServedEndpoint(ctx)
+	if endpoint == "" {
+		endpoint = c.endpoint
+	}
+	c.audit.record(ctx, endpoint, c.auditUser, operation, objectID, request, err)
+}
+
+// withRequestID starts a call with a new request ID, if the backend sends it.
+func (c *Client) withRequestID(ctx context.Context) context.Context {
+	if !c.requestIDs {
+		return ctx
+	}
+	return withRequestID(ctx)
+}
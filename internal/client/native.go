@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// The implementations of the management API calls.
+const (
+	// BackendBinding calls the management API through the objectscale-client binding,
+	// which needs cgo and the Rust library.
+	BackendBinding = "binding"
+	// BackendNative calls the management API over HTTP from Go.
+	BackendNative = "native"
+
+	// defaultBackend is the one used when none is configured.
+	defaultBackend = BackendNative
+)
+
+const namespacesPath = "/object/namespaces"
+
+// namespacePath is the unescaped path of the namespace with the given identifier.
+func namespacePath(id string) string {
+	return namespacesPath + "/namespace/" + id
+}
+
+// maxErrorBody bounds the error document read from a failed call.
+const maxErrorBody = 64 * 1024
+
+// listConcurrency bounds the namespaces read at once by a list, on top of the
+// limiter which may be unbounded.
+const listConcurrency = 8
+
+// nativeAPI is the backend calling the REST management API directly.
+type nativeAPI struct {
+	endpoint string
+	// httpClient sends the calls with the session token.
+	httpClient *http.Client
+	// limiter is shared by every call of the data sources and resources.
+	limiter limiter
+	// version is the release of the cluster, the attributes it does not support are not sent.
+	version Version
+}
+
+var _ ManagementAPI = &nativeAPI{}
+
+func newNativeAPI(endpoint string, httpClient *http.Client, limiter limiter, version Version) *nativeAPI {
+	return &nativeAPI{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: httpClient,
+		limiter:    limiter,
+		version:    version,
+	}
+}
+
+// namespaceCreate is the body creating a namespace, which names it "namespace".
+type namespaceCreate struct {
+	Namespace string `json:"namespace"`
+	namespaceBody
+}
+
+// namespaceBody holds the settable attributes of a namespace. The attributes added
+// by later releases are left out for the older ones, which reject them.
+type namespaceBody struct {
+	DefaultDataServicesVpool     string           `json:"default_data_services_vpool"`
+	AllowedVpoolsList            []string         `json:"allowed_vpools_list"`
+	DisallowedVpoolsList         []string         `json:"disallowed_vpools_list"`
+	NamespaceAdmins              string           `json:"namespace_admins"`
+	UserMapping                  []UserMapping    `json:"user_mapping"`
+	IsEncryptionEnabled          bool             `json:"is_encryption_enabled"`
+	DefaultBucketBlockSize       int64            `json:"default_bucket_block_size"`
+	ExternalGroupAdmins          string           `json:"external_group_admins"`
+	IsStaleAllowed               bool             `json:"is_stale_allowed"`
+	IsObjectLockWithAdoAllowed   *bool            `json:"is_object_lock_with_ado_allowed,omitempty"`
+	IsComplianceEnabled          bool             `json:"is_compliance_enabled"`
+	NotificationSize             int64            `json:"notification_size"`
+	BlockSize                    int64            `json:"block_size"`
+	NotificationSizeInCount      int64            `json:"notification_size_in_count"`
+	BlockSizeInCount             int64            `json:"block_size_in_count"`
+	DefaultAuditDeleteExpiration *int64           `json:"default_audit_delete_expiration,omitempty"`
+	RetentionClasses             RetentionClasses `json:"retention_classes"`
+}
+
+func newNamespaceBody(ns *Namespace, version Version) namespaceBody {
+	body := namespaceBody{
+		DefaultDataServicesVpool: ns.DefaultDataServicesVpool,
+		AllowedVpoolsList:        ns.AllowedVpoolsList,
+		DisallowedVpoolsList:     ns.DisallowedVpoolsList,
+		NamespaceAdmins:          ns.NamespaceAdmins,
+		UserMapping:              ns.UserMapping,
+		IsEncryptionEnabled:      ns.IsEncryptionEnabled,
+		DefaultBucketBlockSize:   ns.DefaultBucketBlockSize,
+		ExternalGroupAdmins:      ns.ExternalGroupAdmins,
+		IsStaleAllowed:           ns.IsStaleAllowed,
+		IsComplianceEnabled:      ns.IsComplianceEnabled,
+		NotificationSize:         ns.NotificationSize,
+		BlockSize:                ns.BlockSize,
+		NotificationSizeInCount:  ns.NotificationSizeInCount,
+		BlockSizeInCount:         ns.BlockSizeInCount,
+		RetentionClasses:         ns.RetentionClasses,
+	}
+	if version.Supports(VersionObjectLockWithAdo) {
+		body.IsObjectLockWithAdoAllowed = &ns.IsObjectLockWithAdoAllowed
+	}
+	if version.Supports(VersionAuditDeleteExpiration) {
+		body.DefaultAuditDeleteExpiration = &ns.DefaultAuditDeleteExpiration
+	}
+	return body
+}
+
+func (n *nativeAPI) CreateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error) {
+	var created Namespace
+	body := namespaceCreate{Namespace: namespace.Name, namespaceBody: newNamespaceBody(namespace, n.version)}
+	if err := n.do(ctx, http.MethodPost, namespacesPath+"/namespace", body, &created); err != nil {
+		return nil, err
+	}
+	// The creation only returns the identifier.
+	return n.GetNamespace(ctx, created.Id)
+}
+
+func (n *nativeAPI) GetNamespace(ctx context.Context, id string) (*Namespace, error) {
+	var namespace Namespace
+	if err := n.do(ctx, http.MethodGet, namespacesPath+"/namespace/"+url.PathEscape(id), nil, &namespace); err != nil {
+		return nil, err
+	}
+	return &namespace, nil
+}
+
+func (n *nativeAPI) UpdateNamespace(ctx context.Context, namespace *Namespace) (*Namespace, error) {
+	if err := n.do(ctx, http.MethodPut, namespacesPath+"/namespace/"+url.PathEscape(namespace.Id), newNamespaceBody(namespace, n.version), nil); err != nil {
+		return nil, err
+	}
+	return n.GetNamespace(ctx, namespace.Id)
+}
+
+func (n *nativeAPI) DeleteNamespace(ctx context.Context, id string) error {
+	return n.do(ctx, http.MethodPost, namespacesPath+"/namespace/"+url.PathEscape(id)+"/deactivate", nil, nil)
+}
+
+func (n *nativeAPI) ListNamespaces(ctx context.Context, name string) ([]Namespace, error) {
+	var list struct {
+		Namespace []Namespace `json:"namespace"`
+	}
+	if err := n.do(ctx, http.MethodGet, namespacesPath, nil, &list); err != nil {
+		return nil, err
+	}
+
+	var items []Namespace
+	for _, item := range list.Namespace {
+		if name == "" || item.Name == name {
+			items = append(items, item)
+		}
+	}
+
+	// The list only holds the identifiers and names, the details are read one by one,
+	// concurrently and each under the limiter. The first failure cancels the others.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+		workers  = make(chan struct{}, listConcurrency)
+		read     = make([]*Namespace, len(items))
+	)
+	for i, item := range items {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			namespace, err := n.GetNamespace(ctx, item.Id)
+			switch {
+			case IsNotFound(err):
+				// Deleted since the list call.
+			case err != nil:
+				failOnce.Do(func() {
+					failure = err
+					cancel()
+				})
+			default:
+				read[i] = namespace
+			}
+		}()
+	}
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+
+	namespaces := make([]Namespace, 0, len(read))
+	for _, namespace := range read {
+		if namespace != nil {
+			namespaces = append(namespaces, *namespace)
+		}
+	}
+	return namespaces, nil
+}
+
+// do sends a JSON request and decodes the JSON response into result, unless it is nil.
+func (n *nativeAPI) do(ctx context.Context, method, path string, body, result interface{}) (err error) {
+	if err := n.limiter.acquire(ctx); err != nil {
+		return err
+	}
+	defer n.limiter.release()
+
+	ctx, span := startHTTPSpan(ctx, method, path)
+	defer func() { endSpan(span, err) }()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, n.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	requestID := requestIDFromContext(ctx)
+	req.Header.Set(RequestIDHeader, requestID)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return &requestError{requestID: requestID, err: fmt.Errorf("%s %s: %w", method, path, err)}
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(AttributeStatusCode.Int(resp.StatusCode))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(method, path, requestID, resp)
+	}
+	if result == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return &requestError{requestID: requestID, err: fmt.Errorf("%s %s: unable to decode the response: %w", method, path, err)}
+	}
+	return nil
+}
+
+// responseError describes a failed call with the error document of the cluster.
+func responseError(method, path, requestID string, resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := newAPIError(resp.StatusCode, data)
+	apiErr.RequestID = requestID
+	apiErr.ServerRequestID = serverRequestID(resp.Header, requestID)
+	return fmt.Errorf("%s %s: %w", method, path, apiErr)
+}
@@ -0,0 +1,401 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeCluster is an in-memory management API serving the namespace calls.
+type fakeCluster struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	namespaces map[string]Namespace
+	// tokens are the session tokens handed out by the logins.
+	tokens []string
+	// requests counts the requests by "METHOD path".
+	requests map[string]int
+	// requestIDs are the request IDs received, in order.
+	requestIDs []string
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	t.Helper()
+	cluster := &fakeCluster{namespaces: map[string]Namespace{}, requests: map[string]int{}}
+	cluster.Server = httptest.NewServer(http.HandlerFunc(cluster.serve))
+	t.Cleanup(cluster.Close)
+	return cluster
+}
+
+func (f *fakeCluster) count(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[key]
+}
+
+// expireTokens makes the cluster reject the tokens handed out so far.
+func (f *fakeCluster) expireTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = nil
+}
+
+func (f *fakeCluster) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[r.Method+" "+r.URL.Path]++
+	f.requestIDs = append(f.requestIDs, r.Header.Get(RequestIDHeader))
+
+	if r.URL.Path == loginPath {
+		if username, password, ok := r.BasicAuth(); !ok || username != "root" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		token := newRequestID()
+		f.tokens = append(f.tokens, token)
+		w.Header().Set(authTokenHeader, token)
+		return
+	}
+	authorized := false
+	for _, token := range f.tokens {
+		authorized = authorized || r.Header.Get(authTokenHeader) == token
+	}
+	if !authorized {
+		writeECSError(w, http.StatusUnauthorized, 0, "Unauthorized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amz-Request-Id", "server-"+r.Header.Get(RequestIDHeader))
+	switch {
+	case r.URL.Path == whoamiPath:
+		_, _ = w.Write([]byte(`{"common_name":"root","roles":["SYSTEM_ADMIN"]}`))
+	case r.URL.Path == nodesPath:
+		_, _ = w.Write([]byte(`{"node":[{"version":"3.8.1.0.1234"},{"version":"3.7.0.0.99"}]}`))
+	case r.Method == http.MethodGet && r.URL.Path == namespacesPath:
+		list := []map[string]string{}
+		for id, namespace := range f.namespaces {
+			list = append(list, map[string]string{"id": id, "name": namespace.Name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"namespace": list})
+	case r.Method == http.MethodPost && r.URL.Path == namespacesPath+"/namespace":
+		var body struct {
+			Namespace string `json:"namespace"`
+			namespaceBody
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Namespace == "" {
+			writeECSError(w, http.StatusBadRequest, 1008, "Error parsing request")
+			return
+		}
+		if _, ok := f.namespaces[body.Namespace]; ok {
+			writeECSError(w, http.StatusBadRequest, ecsCodeAlreadyExists, "Namespace already exists")
+			return
+		}
+		f.namespaces[body.Namespace] = namespaceFromBody(body.Namespace, body.namespaceBody)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": body.Namespace})
+	case strings.HasPrefix(r.URL.Path, namespacesPath+"/namespace/"):
+		id := strings.TrimPrefix(r.URL.Path, namespacesPath+"/namespace/")
+		id, deactivate := strings.CutSuffix(id, "/deactivate")
+		namespace, ok := f.namespaces[id]
+		if !ok {
+			writeECSError(w, http.StatusBadRequest, ecsCodeNotFound, "Unable to find entity specified in URL")
+			return
+		}
+		switch {
+		case deactivate && r.Method == http.MethodPost:
+			delete(f.namespaces, id)
+		case r.Method == http.MethodPut:
+			var body namespaceBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeECSError(w, http.StatusBadRequest, 1008, "Error parsing request")
+				return
+			}
+			f.namespaces[id] = namespaceFromBody(namespace.Name, body)
+		case r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(namespace)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func namespaceFromBody(name string, body namespaceBody) Namespace {
+	return Namespace{
+		Id:                       name,
+		Name:                     name,
+		DefaultDataServicesVpool: body.DefaultDataServicesVpool,
+		NamespaceAdmins:          body.NamespaceAdmins,
+		BlockSize:                body.BlockSize,
+		RetentionClasses:         body.RetentionClasses,
+	}
+}
+
+func writeECSError(w http.ResponseWriter, status, code int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":        code,
+		"description": description,
+		"details":     description + " (details)",
+		"retryable":   false,
+	})
+}
+
+func newNativeTestClient(t *testing.T, cluster *fakeCluster, config Config) *Client {
+	t.Helper()
+	config.Backend = BackendNative
+	config.Endpoints = []string{cluster.URL}
+	if config.Username == "" {
+		config.Username, config.Password = "root", "password"
+	}
+	c, err := NewClient(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNativeLogin(t *testing.T) {
+	cluster := newFakeCluster(t)
+
+	c := newNativeTestClient(t, cluster, Config{})
+	if got := c.Version(); got != (Version{Major: 3, Minor: 7}) {
+		t.Errorf("Version() = %s, want the oldest node release 3.7.0", got)
+	}
+	if cluster.count("GET "+loginPath) != 1 {
+		t.Errorf("%d logins, want 1", cluster.count("GET "+loginPath))
+	}
+
+	_, err := NewClient(context.Background(), Config{
+		Backend:   BackendNative,
+		Endpoints: []string{cluster.URL},
+		Username:  "root",
+		Password:  "wrong",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid username or password") {
+		t.Errorf("login with a wrong password: got %v", err)
+	}
+}
+
+func TestNativeReloginAfterExpiredToken(t *testing.T) {
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{})
+
+	cluster.expireTokens()
+	if _, err := c.ListNamespaces(context.Background(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if cluster.count("GET "+loginPath) != 2 {
+		t.Errorf("%d logins, want a second one after the token expired", cluster.count("GET "+loginPath))
+	}
+}
+
+func TestNativeNamespaceLifecycle(t *testing.T) {
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{})
+	ctx := context.Background()
+
+	created, err := c.CreateNamespace(ctx, &Namespace{
+		Name:                     "ns1",
+		DefaultDataServicesVpool: "urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global",
+		BlockSize:                10,
+		RetentionClasses:         RetentionClasses{RetentionClass: []RetentionClass{{Name: "r1", Period: 1}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Id != "ns1" || created.BlockSize != 10 || len(created.RetentionClasses.RetentionClass) != 1 {
+		t.Errorf("created namespace %+v", created)
+	}
+
+	if _, err := c.CreateNamespace(ctx, &Namespace{Name: "ns1"}); !isKind(err, ErrorKindConflict) {
+		t.Errorf("creating it again: got %v, want a conflict", err)
+	}
+
+	created.BlockSize = 20
+	created.NamespaceAdmins = "admin"
+	updated, err := c.UpdateNamespace(ctx, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.BlockSize != 20 || updated.NamespaceAdmins != "admin" {
+		t.Errorf("updated namespace %+v", updated)
+	}
+
+	if _, err := c.CreateNamespace(ctx, &Namespace{Name: "ns2"}); err != nil {
+		t.Fatal(err)
+	}
+	all, err := c.ListNamespaces(ctx, "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("ListNamespaces() = %v, %v, want 2 namespaces", all, err)
+	}
+	named, err := c.ListNamespaces(ctx, "ns2")
+	if err != nil || len(named) != 1 || named[0].Name != "ns2" {
+		t.Fatalf("ListNamespaces(ns2) = %v, %v", named, err)
+	}
+
+	if err := c.DeleteNamespace(ctx, "ns1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetNamespace(ctx, "ns1"); !IsNotFound(err) {
+		t.Errorf("reading the deleted namespace: got %v, want not found", err)
+	}
+	if err := c.DeleteNamespace(ctx, "ns1"); !IsNotFound(err) {
+		t.Errorf("deleting it again: got %v, want not found", err)
+	}
+}
+
+func TestNativeErrorDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantKind    ErrorKind
+		wantCode    int
+		wantMessage string
+		retryable   bool
+	}{
+		{
+			name:        "not found code on a 400",
+			status:      http.StatusBadRequest,
+			body:        `{"code":1004,"description":"Unable to find entity specified in URL","details":"ns9"}`,
+			wantKind:    ErrorKindNotFound,
+			wantCode:    1004,
+			wantMessage: "Unable to find entity specified in URL: ns9 (HTTP 400, error code 1004)",
+		},
+		{
+			name:        "validation",
+			status:      http.StatusBadRequest,
+			body:        `{"code":1008,"description":"Error parsing request","details":"Error parsing request"}`,
+			wantKind:    ErrorKindValidation,
+			wantCode:    1008,
+			wantMessage: "Error parsing request (HTTP 400, error code 1008)",
+		},
+		{
+			name:        "forbidden",
+			status:      http.StatusForbidden,
+			body:        `{"code":3000,"description":"Insufficient permissions"}`,
+			wantKind:    ErrorKindForbidden,
+			wantCode:    3000,
+			wantMessage: "Insufficient permissions (HTTP 403, error code 3000)",
+		},
+		{
+			name:        "unavailable without a document",
+			status:      http.StatusServiceUnavailable,
+			body:        `<html>busy</html>`,
+			wantKind:    ErrorKindUnavailable,
+			wantMessage: "Service Unavailable (HTTP 503)",
+			retryable:   true,
+		},
+		{
+			name:        "retryable document",
+			status:      http.StatusInternalServerError,
+			body:        `{"code":6503,"description":"Internal error","retryable":true}`,
+			wantKind:    ErrorKindUnavailable,
+			wantCode:    6503,
+			wantMessage: "Internal error (HTTP 500, error code 6503)",
+			retryable:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Amz-Request-Id", "cluster-id")
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			api := newNativeAPI(server.URL, server.Client(), nil, Version{})
+			ctx := withRequestID(context.Background())
+			_, err := api.GetNamespace(ctx, "ns9")
+
+			apiErr, ok := AsAPIError(err)
+			if !ok {
+				t.Fatalf("got %v, want an APIError", err)
+			}
+			if apiErr.Kind() != test.wantKind || apiErr.Code != test.wantCode || apiErr.Retryable != test.retryable {
+				t.Errorf("got kind %q, code %d, retryable %v", apiErr.Kind(), apiErr.Code, apiErr.Retryable)
+			}
+			if apiErr.Error() != test.wantMessage {
+				t.Errorf("got message %q, want %q", apiErr.Error(), test.wantMessage)
+			}
+			if sent, server := RequestIDs(err); sent != requestIDFromContext(ctx) || server != "cluster-id" {
+				t.Errorf("got request IDs %q and %q", sent, server)
+			}
+		})
+	}
+}
+
+func isKind(err error, kind ErrorKind) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Kind() == kind
+}
+
+func TestNativeTransportErrorKeepsTheRequestID(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	api := newNativeAPI(server.URL, server.Client(), nil, Version{})
+	ctx := withRequestID(context.Background())
+	_, err := api.GetNamespace(ctx, "ns1")
+
+	var reqErr *requestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("got %v, want a requestError", err)
+	}
+	if sent, _ := RequestIDs(err); sent != requestIDFromContext(ctx) {
+		t.Errorf("got request ID %q, want the one of the call", sent)
+	}
+}
+
+func TestNativeTracesTheRequestsUnderTheirCall(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	cluster := newFakeCluster(t)
+	c := newNativeTestClient(t, cluster, Config{})
+	if _, err := c.GetNamespace(context.Background(), "missing"); !IsNotFound(err) {
+		t.Fatalf("got %v, want not found", err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, ended := range recorder.Ended() {
+		spans[ended.Name()] = ended
+	}
+	call, request := spans["objectscale.GetNamespace"], spans["HTTP GET"]
+	if call == nil || request == nil {
+		t.Fatalf("got spans %v, want the call and its request", spans)
+	}
+	if request.Parent().SpanID() != call.SpanContext().SpanID() {
+		t.Errorf("the request span is not a child of the span of its call")
+	}
+	attributes := map[string]string{}
+	for _, attr := range request.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attributes[string(AttributeStatusCode)] == "" || attributes[string(AttributeStatusCode)] == "200" {
+		t.Errorf("got status code attribute %q, want the failed status", attributes[string(AttributeStatusCode)])
+	}
+	for _, attr := range call.Attributes() {
+		if attr.Key == AttributeRequestID && attr.Value.AsString() == cluster.requestIDs[len(cluster.requestIDs)-1] {
+			return
+		}
+	}
+	t.Errorf("the call span does not carry the request ID sent")
+}
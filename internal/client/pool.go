@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Endpoint selection strategies.
+const (
+	// SelectionFailover always prefers the first healthy endpoint in the configured order.
+	SelectionFailover = "failover"
+	// SelectionRoundRobin spreads the calls over the healthy endpoints.
+	SelectionRoundRobin = "round_robin"
+)
+
+// unhealthyCooldown is how long an unreachable endpoint is skipped.
+const unhealthyCooldown = time.Minute
+
+type poolEndpoint struct {
+	url *url.URL
+	// unhealthyUntil is zero while the endpoint is healthy.
+	unhealthyUntil time.Time
+}
+
+// poolTransport sends each request to one of several management endpoints.
+// An endpoint that cannot be reached is marked unhealthy and the request moves
+// on to the next one, an endpoint answering that it is unavailable is marked
+// unhealthy so the next retry goes elsewhere.
+type poolTransport struct {
+	base      http.RoundTripper
+	selection string
+	// logCtx carries the provider logger, it is never used to cancel requests.
+	logCtx context.Context
+
+	mu        sync.Mutex
+	endpoints []*poolEndpoint
+	next      atomic.Uint64
+}
+
+func newPoolTransport(ctx context.Context, base http.RoundTripper, endpoints []string, selection string) (*poolTransport, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoint configured")
+	}
+	if selection == "" {
+		selection = SelectionFailover
+	}
+	if selection != SelectionFailover && selection != SelectionRoundRobin {
+		return nil, fmt.Errorf("unknown endpoint selection %q", selection)
+	}
+
+	pool := &poolTransport{
+		base:      base,
+		selection: selection,
+		logCtx:    ctx,
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+		}
+		pool.endpoints = append(pool.endpoints, &poolEndpoint{url: u})
+	}
+	return pool, nil
+}
+
+type servedEndpointKey struct{}
+
+// withServedEndpoint starts a call whose serving endpoint is kept, see contextServedEndpoint.
+func withServedEndpoint(ctx context.Context) context.Context {
+	return context.WithValue(ctx, servedEndpointKey{}, new(atomic.Pointer[string]))
+}
+
+// contextServedEndpoint returns the endpoint that answered the last request of the call, empty
+// when none did or the call was not started with withServedEndpoint.
+func contextServedEndpoint(ctx context.Context) string {
+	if served, ok := ctx.Value(servedEndpointKey{}).(*atomic.Pointer[string]); ok {
+		if endpoint := served.Load(); endpoint != nil {
+			return *endpoint
+		}
+	}
+	return ""
+}
+
+// primary returns the first configured endpoint.
+func (t *poolTransport) primary() string {
+	return t.endpoints[0].url.String()
+}
+
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for i, endpoint := range t.order() {
+		out := req.Clone(req.Context())
+		out.URL.Scheme = endpoint.url.Scheme
+		out.URL.Host = endpoint.url.Host
+		out.Host = endpoint.url.Host
+		if i > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.base.RoundTrip(out)
+		if err != nil {
+			if !isDialError(err) {
+				return nil, err
+			}
+			t.markUnhealthy(endpoint, err.Error())
+			lastErr = err
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				// The body has been consumed and cannot be sent again.
+				return nil, err
+			}
+			continue
+		}
+
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			t.markUnhealthy(endpoint, resp.Status)
+		default:
+			t.markHealthy(endpoint)
+		}
+		served := endpoint.url.String()
+		if holder, ok := req.Context().Value(servedEndpointKey{}).(*atomic.Pointer[string]); ok {
+			holder.Store(&served)
+		}
+		tflog.Debug(t.logCtx, "management API call served", map[string]interface{}{
+			"endpoint": served,
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"status":   resp.StatusCode,
+			"latency":  time.Since(start).String(),
+		})
+		return resp, nil
+	}
+	return nil, fmt.Errorf("no management endpoint is reachable: %w", lastErr)
+}
+
+// order returns the endpoints in the order they should be tried, healthy ones first.
+func (t *poolTransport) order() []*poolEndpoint {
+	start := 0
+	if t.selection == SelectionRoundRobin {
+		start = int(t.next.Add(1)-1) % len(t.endpoints)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]*poolEndpoint, 0, len(t.endpoints))
+	var unhealthy []*poolEndpoint
+	for i := range t.endpoints {
+		endpoint := t.endpoints[(start+i)%len(t.endpoints)]
+		if now.Before(endpoint.unhealthyUntil) {
+			unhealthy = append(unhealthy, endpoint)
+		} else {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	// Unhealthy endpoints are still tried last, they may have recovered.
+	return append(healthy, unhealthy...)
+}
+
+func (t *poolTransport) markUnhealthy(endpoint *poolEndpoint, reason string) {
+	t.mu.Lock()
+	endpoint.unhealthyUntil = time.Now().Add(unhealthyCooldown)
+	t.mu.Unlock()
+
+	if len(t.endpoints) > 1 {
+		tflog.Warn(t.logCtx, "management endpoint marked unhealthy", map[string]interface{}{
+			"endpoint": endpoint.url.String(),
+			"reason":   reason,
+		})
+	}
+}
+
+func (t *poolTransport) markHealthy(endpoint *poolEndpoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	endpoint.unhealthyUntil = time.Time{}
+}
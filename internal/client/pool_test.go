@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// poolTestServers starts the servers answering with their name and the given status.
+func poolTestServers(t *testing.T, statuses ...int) []string {
+	t.Helper()
+	var endpoints []string
+	for i, status := range statuses {
+		name := string(rune('a' + i))
+		status := status
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Server", name)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		endpoints = append(endpoints, server.URL)
+	}
+	return endpoints
+}
+
+func poolServed(t *testing.T, pool *poolTransport) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "http://placeholder"+namespacesPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := pool.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.Header.Get("X-Server")
+}
+
+func TestPoolTransport(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		endpoints func(t *testing.T) []string
+		selection string
+		want      []string
+	}{
+		{
+			name:      "failover stays on the first endpoint",
+			endpoints: func(t *testing.T) []string { return poolTestServers(t, 200, 200) },
+			want:      []string{"a", "a", "a"},
+		},
+		{
+			name:      "round robin spreads the calls",
+			endpoints: func(t *testing.T) []string { return poolTestServers(t, 200, 200, 200) },
+			selection: SelectionRoundRobin,
+			want:      []string{"a", "b", "c", "a"},
+		},
+		{
+			name: "failover skips an unreachable endpoint",
+			endpoints: func(t *testing.T) []string {
+				return append([]string{closed.URL}, poolTestServers(t, 200)...)
+			},
+			want: []string{"a", "a"},
+		},
+		{
+			name:      "an unavailable endpoint is tried last",
+			endpoints: func(t *testing.T) []string { return poolTestServers(t, 503, 200) },
+			want:      []string{"a", "b", "b"},
+		},
+		{
+			name:      "round robin skips an unavailable endpoint",
+			endpoints: func(t *testing.T) []string { return poolTestServers(t, 200, 503, 200) },
+			selection: SelectionRoundRobin,
+			want:      []string{"a", "b", "c", "a", "c"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool, err := newPoolTransport(context.Background(), http.DefaultTransport, test.endpoints(t), test.selection)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range test.want {
+				if got := poolServed(t, pool); got != want {
+					t.Errorf("call %d served by %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolTransportConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []string
+		selection string
+		wantErr   bool
+	}{
+		{name: "no endpoint", wantErr: true},
+		{name: "missing host", endpoints: []string{"objectscale:4443"}, wantErr: true},
+		{name: "unknown selection", endpoints: []string{"https://a:4443"}, selection: "random", wantErr: true},
+		{name: "trailing slash", endpoints: []string{"https://a:4443/"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool, err := newPoolTransport(context.Background(), http.DefaultTransport, test.endpoints, test.selection)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if err == nil && pool.primary() != "https://a:4443" {
+				t.Errorf("primary() = %q", pool.primary())
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the file holding the named profiles, relative to the home directory.
+const DefaultConfigFile = ".objectscale/config"
+
+// Profile holds the connection settings of one cluster. Empty values are not set.
+type Profile struct {
+	Endpoint string `yaml:"endpoint"`
+	Username string `yaml:"username"`
+	// CABundle is the path of a PEM bundle of the CAs trusted to sign the endpoint certificate.
+	CABundle string `yaml:"ca_bundle"`
+	Insecure *bool  `yaml:"insecure"`
+	Token    string `yaml:"token"`
+}
+
+// LoadProfile reads the named profile from the config file, the default one when path is empty.
+// The file is either INI, with a [name] or [profile name] section per profile, or YAML,
+// with a top-level mapping of profile names.
+func LoadProfile(path, name string) (Profile, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Profile{}, fmt.Errorf("unable to locate the objectscale config file: %w", err)
+		}
+		path = filepath.Join(home, DefaultConfigFile)
+	}
+
+	content, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return Profile{}, fmt.Errorf("unable to read the objectscale config file: %w", err)
+	}
+
+	var profiles map[string]Profile
+	if isINI(content) {
+		profiles, err = parseINIProfiles(content)
+	} else {
+		profiles, err = parseYAMLProfiles(content)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("unable to parse the objectscale config file %s: %w", path, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("the profile %q is not defined in the objectscale config file %s", name, path)
+	}
+	profile.CABundle = expandHome(profile.CABundle)
+	return profile, nil
+}
+
+// isINI reports whether the first meaningful line of the file is a section header.
+func isINI(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		return strings.HasPrefix(line, "[")
+	}
+	return false
+}
+
+// parseYAMLProfiles parses a YAML config file, rejecting the unknown keys as the INI parser does.
+func parseYAMLProfiles(content []byte) (map[string]Profile, error) {
+	var profiles map[string]Profile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func parseINIProfiles(content []byte) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name = strings.TrimSpace(strings.TrimPrefix(strings.Trim(line, "[]"), "profile "))
+			profiles[name] = Profile{}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected a [profile] section or a key = value pair", number)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		profile := profiles[name]
+		switch key {
+		case "endpoint":
+			profile.Endpoint = value
+		case "username":
+			profile.Username = value
+		case "ca_bundle":
+			profile.CABundle = value
+		case "insecure":
+			insecure, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: insecure must be a boolean", number)
+			}
+			profile.Insecure = &insecure
+		case "token":
+			profile.Token = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", number, key)
+		}
+		profiles[name] = profile
+	}
+	return profiles, scanner.Err()
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
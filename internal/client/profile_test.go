@@ -0,0 +1,146 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	insecure := true
+
+	tests := []struct {
+		name      string
+		content   string
+		profile   string
+		want      Profile
+		wantError string
+	}{
+		{
+			name:    "INI section",
+			content: "[prod]\nendpoint = https://prod.example.com:4443\nusername = \"root\"\ninsecure = true\n",
+			profile: "prod",
+			want:    Profile{Endpoint: "https://prod.example.com:4443", Username: "root", Insecure: &insecure},
+		},
+		{
+			name:    "INI profile section",
+			content: "# clusters\n[default]\nendpoint = https://default.example.com\n\n[profile prod]\nendpoint = https://prod.example.com\ntoken = 't1'\n",
+			profile: "prod",
+			want:    Profile{Endpoint: "https://prod.example.com", Token: "t1"},
+		},
+		{
+			name:    "INI home expansion",
+			content: "[prod]\nca_bundle = ~/certs/ca.pem\n",
+			profile: "prod",
+			want:    Profile{CABundle: filepath.Join(home, "certs/ca.pem")},
+		},
+		{
+			name:      "INI unknown key",
+			content:   "[prod]\nendpoint = https://prod.example.com\npasword = secret\n",
+			profile:   "prod",
+			wantError: `line 3: unknown key "pasword"`,
+		},
+		{
+			name:      "INI line without a key",
+			content:   "[prod]\nhttps://prod.example.com\n",
+			profile:   "prod",
+			wantError: "line 2: expected a [profile] section or a key = value pair",
+		},
+		{
+			name:      "INI invalid boolean",
+			content:   "[prod]\ninsecure = maybe\n",
+			profile:   "prod",
+			wantError: "line 2: insecure must be a boolean",
+		},
+		{
+			name:    "YAML",
+			content: "# clusters\nprod:\n  endpoint: https://prod.example.com\n  username: root\n  insecure: true\n",
+			profile: "prod",
+			want:    Profile{Endpoint: "https://prod.example.com", Username: "root", Insecure: &insecure},
+		},
+		{
+			name:    "YAML home expansion",
+			content: "prod:\n  ca_bundle: ~/certs/ca.pem\n",
+			profile: "prod",
+			want:    Profile{CABundle: filepath.Join(home, "certs/ca.pem")},
+		},
+		{
+			name:      "YAML unknown key",
+			content:   "prod:\n  endpoint: https://prod.example.com\n  pasword: secret\n",
+			profile:   "prod",
+			wantError: "field pasword not found",
+		},
+		{
+			name:      "empty file",
+			content:   "",
+			profile:   "prod",
+			wantError: `the profile "prod" is not defined`,
+		},
+		{
+			name:      "unknown profile",
+			content:   "[dev]\nendpoint = https://dev.example.com\n",
+			profile:   "prod",
+			wantError: `the profile "prod" is not defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadProfile(path, tt.profile)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got %v, want an error containing %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProfileExpandsTheConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".objectscale"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, DefaultConfigFile), []byte("[prod]\nendpoint = https://prod.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"", "~/" + DefaultConfigFile} {
+		got, err := LoadProfile(path, "prod")
+		if err != nil {
+			t.Fatalf("LoadProfile(%q): %v", path, err)
+		}
+		if got.Endpoint != "https://prod.example.com" {
+			t.Errorf("LoadProfile(%q) = %+v, want the prod endpoint", path, got)
+		}
+	}
+}
+
+func TestIsINI(t *testing.T) {
+	for content, want := range map[string]bool{
+		"[prod]\n":                       true,
+		"\n# comment\n; other\n[prod]\n": true,
+		"prod:\n  endpoint: x\n":         false,
+		"# [prod]\nprod: {}\n":           false,
+		"":                               false,
+	} {
+		if got := isINI([]byte(content)); got != want {
+			t.Errorf("isINI(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
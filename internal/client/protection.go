@@ -0,0 +1,53 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// ErrProtectedNamespace is returned when deleting a namespace matching the protected namespaces.
+var ErrProtectedNamespace = errors.New("the namespace is protected against deletion")
+
+// ValidateNamespacePattern checks the syntax of a protected namespace name or glob pattern.
+func ValidateNamespacePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// namespaceProtection refuses to delete the namespaces matching one of the patterns,
+// unless their deletion is explicitly allowed by name.
+type namespaceProtection struct {
+	patterns  []string
+	overrides map[string]bool
+}
+
+func newNamespaceProtection(patterns, overrides []string) namespaceProtection {
+	protection := namespaceProtection{
+		patterns:  patterns,
+		overrides: make(map[string]bool, len(overrides)),
+	}
+	for _, name := range overrides {
+		protection.overrides[name] = true
+	}
+	return protection
+}
+
+// CheckNamespaceDeletion returns ErrProtectedNamespace if the namespace must not be deleted.
+func (c *Client) CheckNamespaceDeletion(name string) error {
+	return c.protection.check(name)
+}
+
+func (p namespaceProtection) check(name string) error {
+	if p.overrides[name] {
+		return nil
+	}
+	for _, pattern := range p.patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return fmt.Errorf("%w: %q matches the protected pattern %q", ErrProtectedNamespace, name, pattern)
+		}
+	}
+	return nil
+}
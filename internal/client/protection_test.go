@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNamespaceProtection(t *testing.T) {
+	tests := []struct {
+		name          string
+		patterns      []string
+		overrides     []string
+		namespace     string
+		wantProtected bool
+	}{
+		{name: "no patterns", namespace: "prod-1"},
+		{name: "exact name", patterns: []string{"prod-1"}, namespace: "prod-1", wantProtected: true},
+		{name: "other name", patterns: []string{"prod-1"}, namespace: "prod-10"},
+		{name: "star", patterns: []string{"prod-*"}, namespace: "prod-eu-1", wantProtected: true},
+		{name: "star needs the prefix", patterns: []string{"prod-*"}, namespace: "preprod-1"},
+		{name: "question mark", patterns: []string{"prod-?"}, namespace: "prod-1", wantProtected: true},
+		{name: "question mark is one character", patterns: []string{"prod-?"}, namespace: "prod-10"},
+		{name: "character class", patterns: []string{"prod-[ab]"}, namespace: "prod-b", wantProtected: true},
+		{name: "second pattern", patterns: []string{"audit", "prod-*"}, namespace: "prod-1", wantProtected: true},
+		{name: "override", patterns: []string{"prod-*"}, overrides: []string{"prod-1"}, namespace: "prod-1"},
+		{name: "override of another namespace", patterns: []string{"prod-*"}, overrides: []string{"prod-1"}, namespace: "prod-2", wantProtected: true},
+		{name: "override is not a pattern", patterns: []string{"prod-*"}, overrides: []string{"prod-*"}, namespace: "prod-1", wantProtected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newNamespaceProtection(tt.patterns, tt.overrides).check(tt.namespace)
+			if got := errors.Is(err, ErrProtectedNamespace); got != tt.wantProtected {
+				t.Errorf("check(%q) = %v, want protected %v", tt.namespace, err, tt.wantProtected)
+			}
+		})
+	}
+}
+
+func TestValidateNamespacePattern(t *testing.T) {
+	for pattern, wantError := range map[string]bool{
+		"prod-1":    false,
+		"prod-*":    false,
+		"prod-[ab]": false,
+		"prod-[":    true,
+		`prod-\`:    true,
+	} {
+		if err := ValidateNamespacePattern(pattern); (err != nil) != wantError {
+			t.Errorf("ValidateNamespacePattern(%q) = %v, want error %v", pattern, err, wantError)
+		}
+	}
+}
+
+func TestDeleteProtectedNamespace(t *testing.T) {
+	cluster := newFakeCluster(t)
+	cluster.namespaces["prod-1"] = Namespace{Id: "prod-1", Name: "prod-1"}
+	cluster.namespaces["test-1"] = Namespace{Id: "test-1", Name: "test-1"}
+	c := newNativeTestClient(t, cluster, Config{ProtectedNamespaces: []string{"prod-*"}})
+	ctx := context.Background()
+
+	if err := c.DeleteNamespace(ctx, "prod-1"); !errors.Is(err, ErrProtectedNamespace) {
+		t.Fatalf("got %v, want the deletion refused", err)
+	}
+	if n := cluster.count("POST " + namespacePath("prod-1") + "/deactivate"); n != 0 {
+		t.Errorf("%d deletions of the protected namespace sent, want none", n)
+	}
+	if err := c.DeleteNamespace(ctx, "test-1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := cluster.count("POST " + namespacePath("test-1") + "/deactivate"); n != 1 {
+		t.Errorf("%d deletions of the unprotected namespace sent, want 1", n)
+	}
+}
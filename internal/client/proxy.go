@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrProxyAuthentication is returned when the proxy rejects the provider credentials.
+var ErrProxyAuthentication = errors.New("proxy authentication failed")
+
+// newProxyFunc returns the proxy selection of the management API traffic: the
+// proxy URL when set, else the HTTPS_PROXY environment variable. NO_PROXY is
+// honoured in both cases.
+func newProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	config := httpproxy.FromEnvironment()
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %s: the scheme must be http, https or socks5", u.Redacted())
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %s: missing host", u.Redacted())
+		}
+		config.HTTPProxy = proxyURL
+		config.HTTPSProxy = proxyURL
+	}
+
+	proxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// checkProxyConnect turns the rejection of the proxy credentials into ErrProxyAuthentication.
+func checkProxyConnect(_ context.Context, proxyURL *url.URL, _ *http.Request, resp *http.Response) error {
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		return fmt.Errorf("%w: %s answered %s", ErrProxyAuthentication, proxyURL.Redacted(), resp.Status)
+	}
+	return nil
+}
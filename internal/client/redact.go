@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// isSensitiveKey tells whether a header, query parameter or body field holds a secret,
+// ex. password, root_user_password, X-SDS-AUTH-TOKEN or secret_key_1.
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+	for _, sensitive := range []string{"password", "passwd", "token", "secret", "authorization", "cookie", "privatekey", "credential"} {
+		if strings.Contains(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders flattens the headers, hiding the values of sensitive ones.
+func redactHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		if isSensitiveKey(key) {
+			out[key] = redacted
+			continue
+		}
+		out[key] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// redactURL hides the user info and the values of sensitive query parameters.
+func redactURL(u *url.URL) string {
+	out := *u
+	out.User = nil
+	if out.RawQuery != "" {
+		query := out.Query()
+		for key := range query {
+			if isSensitiveKey(key) {
+				query[key] = []string{redacted}
+			}
+		}
+		out.RawQuery = query.Encode()
+	}
+	return out.String()
+}
+
+// xmlSensitiveElement matches the XML elements holding a secret.
+var xmlSensitiveElement = regexp.MustCompile(`(?is)<([\w:-]*(?:password|passwd|token|secret|credential)[\w:-]*)>[^<]*</([\w:-]+)>`)
+
+// redactBody hides the values of the sensitive fields of a JSON or XML body.
+func redactBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err == nil {
+			if out, err := json.Marshal(redactJSON(value)); err == nil {
+				return string(out)
+			}
+		}
+		// Never log a body that could not be parsed to redact it.
+		return "<unparsable JSON body omitted>"
+	}
+
+	return xmlSensitiveElement.ReplaceAllString(string(trimmed), "<$1>"+redacted+"</$2>")
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactJSON(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
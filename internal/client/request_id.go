@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+)
+
+// RequestIDHeader carries the identifier of a management call, so the call can be
+// found in the audit log of the cluster.
+const RequestIDHeader = "X-Request-ID"
+
+// serverRequestIDHeaders may carry the identifier the cluster gave to a request.
+var serverRequestIDHeaders = []string{"X-Request-ID", "X-Amz-Request-Id"}
+
+type requestIDKey struct{}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	encoded := hex.EncodeToString(id[:])
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+// contextRequestID returns the identifier of the management call, empty outside of a call
+// or when the backend does not send it.
+func contextRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDFromContext returns the identifier of the management call, a new one outside of a call.
+func requestIDFromContext(ctx context.Context) string {
+	if id := contextRequestID(ctx); id != "" {
+		return id
+	}
+	return newRequestID()
+}
+
+// serverRequestID returns the identifier of the request given by the cluster, unless it echoes ours.
+func serverRequestID(header http.Header, sent string) string {
+	for _, name := range serverRequestIDHeaders {
+		if id := header.Get(name); id != "" && id != sent {
+			return id
+		}
+	}
+	return ""
+}
+
+// requestError attaches the request ID of a management call to its failure.
+type requestError struct {
+	requestID string
+	err       error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// RequestIDs returns the request ID sent with the failed management call, and the one given
+// by the cluster if any. Both are empty when the call did not reach the cluster, or when the
+// backend did not report them.
+func RequestIDs(err error) (sent, server string) {
+	if apiErr, ok := AsAPIError(err); ok {
+		sent, server = apiErr.RequestID, apiErr.ServerRequestID
+	}
+	var reqErr *requestError
+	if sent == "" && errors.As(err, &reqErr) {
+		sent = reqErr.requestID
+	}
+	return sent, server
+}
+
+// withRequestID starts a management call with a new request ID, which the backend
+// sends with every request of the call.
+func withRequestID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, newRequestID())
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Default retry settings, used when the provider does not set them.
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryMinBackoff = time.Second
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryConfig controls how transient failures of the management API are retried.
+type RetryConfig struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// retryTransport retries requests failing with a transient error.
+//
+// Idempotent requests are retried on connection failures and on the statuses
+// ObjectScale returns during a node failover. Other requests, e.g. the POST
+// creating a namespace, are only retried when the connection could not be
+// established, as the request then never reached the server.
+//
+// A request is idempotent when it is a read, or when its management call was
+// marked with withIdempotent: the method does not tell, ex. a namespace is deleted
+// with a POST.
+type retryTransport struct {
+	base   http.RoundTripper
+	config RetryConfig
+	// logCtx carries the provider logger, it is never used to cancel requests.
+	logCtx context.Context
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		out := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out = req.Clone(req.Context())
+			out.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(out)
+		if attempt >= t.config.MaxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body has been consumed and cannot be sent again.
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Debug(t.logCtx, "retrying management API call", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the exponential delay before the next attempt with jitter,
+// or the delay requested by the server, bounded by the maximum backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.config.MaxBackoff)
+		}
+	}
+
+	wait := t.config.MinBackoff << attempt
+	if wait <= 0 || wait > t.config.MaxBackoff {
+		wait = t.config.MaxBackoff
+	}
+	if half := wait / 2; half > 0 {
+		wait = half + rand.N(half)
+	}
+	return wait
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if isDialError(err) {
+			return true
+		}
+		return isIdempotent(req) && isTransientError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+	return false
+}
+
+type idempotentKey struct{}
+
+// withIdempotent marks the requests of a management call as safe to send again,
+// the call having the same effect however many times it reaches the cluster.
+func withIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	idempotent, _ := req.Context().Value(idempotentKey{}).(bool)
+	return idempotent
+}
+
+// isDialError tells whether the connection failed before anything was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	idempotent := withIdempotent(context.Background())
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	resetErr := &net.OpError{Op: "read", Err: errors.New("reset")}
+
+	tests := []struct {
+		name   string
+		method string
+		ctx    context.Context
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "read on 503", method: http.MethodGet, status: http.StatusServiceUnavailable, want: true},
+		{name: "read on 429", method: http.MethodGet, status: http.StatusTooManyRequests, want: true},
+		{name: "read on 500", method: http.MethodGet, status: http.StatusInternalServerError, want: false},
+		{name: "read on 404", method: http.MethodGet, status: http.StatusNotFound, want: false},
+		{name: "creation on 503", method: http.MethodPost, status: http.StatusServiceUnavailable, want: false},
+		{name: "idempotent POST on 503", method: http.MethodPost, ctx: idempotent, status: http.StatusServiceUnavailable, want: true},
+		{name: "idempotent POST on 429", method: http.MethodPost, ctx: idempotent, status: http.StatusTooManyRequests, want: true},
+		{name: "unmarked PUT on 503", method: http.MethodPut, status: http.StatusServiceUnavailable, want: false},
+		{name: "creation on dial error", method: http.MethodPost, err: dialErr, want: true},
+		{name: "creation on reset", method: http.MethodPost, err: resetErr, want: false},
+		{name: "read on EOF", method: http.MethodGet, err: io.ErrUnexpectedEOF, want: true},
+		{name: "cancelled", method: http.MethodGet, ctx: idempotent, err: context.Canceled, want: false},
+		{name: "deadline", method: http.MethodGet, err: context.DeadlineExceeded, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := test.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := httptest.NewRequest(test.method, "https://objectscale:4443/object/namespaces", nil).WithContext(ctx)
+			var resp *http.Response
+			if test.err == nil {
+				resp = &http.Response{StatusCode: test.status, Header: http.Header{}}
+			}
+			if got := shouldRetry(req, resp, test.err); got != test.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetriesIdempotentCalls(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantStatus int
+		wantCalls  int32
+	}{
+		{name: "delete marked idempotent", ctx: withIdempotent(context.Background()), wantStatus: http.StatusOK, wantCalls: 3},
+		{name: "unmarked POST", ctx: context.Background(), wantStatus: http.StatusServiceUnavailable, wantCalls: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			transport := &retryTransport{
+				base:   http.DefaultTransport,
+				config: RetryConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+				logCtx: context.Background(),
+			}
+			req, _ := http.NewRequestWithContext(test.ctx, http.MethodPost, server.URL+"/object/namespaces/namespace/ns1/deactivate", strings.NewReader("{}"))
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.wantStatus || calls.Load() != test.wantCalls {
+				t.Errorf("got status %d after %d calls, want %d after %d", resp.StatusCode, calls.Load(), test.wantStatus, test.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// errNoCredentials is returned when a token expires and there is no password to log in again.
+var errNoCredentials = errors.New("the session token is no longer valid and no username and password are configured to log in again")
+
+// session owns the token used for every call to the management API.
+// It logs in on first use and again when the cluster rejects the token.
+type session struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	// mu is held during a login, so concurrent callers wait for and share one login.
+	mu    sync.Mutex
+	token string
+	// onLogin is called with the token of every successful login.
+	onLogin func(token string)
+}
+
+func newSession(endpoint, username, password string, httpClient *http.Client) *session {
+	return &session{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// current returns the session token, logging in if there is none yet.
+func (s *session) current() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" {
+		return s.token, nil
+	}
+	return s.loginLocked()
+}
+
+// refresh logs in again after the cluster rejected the stale token, unless
+// another caller already replaced it.
+func (s *session) refresh(stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.token != stale {
+		return s.token, nil
+	}
+	return s.loginLocked()
+}
+
+func (s *session) loginLocked() (string, error) {
+	if s.username == "" || s.password == "" {
+		return "", errNoCredentials
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+loginPath, nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(s.username, s.password)
+	req.Header.Set(RequestIDHeader, newRequestID())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to log in to %s: %w", s.endpoint, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("unable to log in to %s: invalid username or password", s.endpoint)
+	}
+	token := resp.Header.Get(authTokenHeader)
+	if resp.StatusCode != http.StatusOK || token == "" {
+		return "", fmt.Errorf("unable to log in to %s: unexpected status %s", s.endpoint, resp.Status)
+	}
+
+	s.token = token
+	if s.onLogin != nil {
+		s.onLogin(token)
+	}
+	return token, nil
+}
+
+// sessionTransport sends every request with the current session token, and
+// retries a request once with a new token when the cluster answers 401.
+type sessionTransport struct {
+	base    http.RoundTripper
+	session *session
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Keep the body so the request can be sent again after the login.
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := t.session.current()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withToken(req, body, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	fresh, err := t.session.refresh(token)
+	if err != nil {
+		// Keep the original 401 for the caller.
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return t.base.RoundTrip(withToken(req, body, fresh))
+}
+
+// withToken returns a copy of the request carrying the token and a fresh body.
+func withToken(req *http.Request, body []byte, token string) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set(authTokenHeader, token)
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		out.ContentLength = int64(len(body))
+	}
+	return out
+}
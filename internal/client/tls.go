@@ -0,0 +1,67 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// ParseCACertificate parses a PEM bundle of one or more CA certificates.
+func ParseCACertificate(pem string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(pem)) {
+		return nil, fmt.Errorf("no valid PEM encoded certificate found in the CA bundle")
+	}
+	return pool, nil
+}
+
+// LoadClientCertificate parses a PEM encoded certificate and private key pair,
+// and checks that the certificate is currently valid.
+func LoadClientCertificate(certificate, key string) (tls.Certificate, error) {
+	pair, err := tls.X509KeyPair([]byte(certificate), []byte(key))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate or key: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	now := time.Now()
+	if now.Before(leaf.NotBefore) {
+		return tls.Certificate{}, fmt.Errorf("client certificate %q is not valid before %s", leaf.Subject.String(), leaf.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("client certificate %q expired at %s", leaf.Subject.String(), leaf.NotAfter.Format(time.RFC3339))
+	}
+	pair.Leaf = leaf
+
+	return pair, nil
+}
+
+// newTLSConfig builds the TLS configuration used to talk to the management API.
+func newTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.Insecure,
+	}
+
+	if config.CACertificate != "" {
+		pool, err := ParseCACertificate(config.CACertificate)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCertificate != "" || config.ClientKey != "" {
+		pair, err := LoadClientCertificate(config.ClientCertificate, config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	return tlsConfig, nil
+}
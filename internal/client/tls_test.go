@@ -0,0 +1,210 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate is a generated certificate with its PEM encoded key.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCertificate generates a certificate valid between notBefore and notAfter,
+// signed by the issuer or self-signed when the issuer is nil.
+func newTestCertificate(t *testing.T, name string, issuer *testCertificate, notBefore, notAfter time.Time) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func TestParseCACertificate(t *testing.T) {
+	now := time.Now()
+	first := newTestCertificate(t, "first CA", nil, now.Add(-time.Hour), now.Add(time.Hour))
+	second := newTestCertificate(t, "second CA", nil, now.Add(-time.Hour), now.Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		pem       string
+		wantError bool
+	}{
+		{name: "one certificate", pem: first.certPEM},
+		{name: "bundle", pem: first.certPEM + second.certPEM},
+		{name: "empty", pem: "", wantError: true},
+		{name: "not PEM", pem: "not a certificate", wantError: true},
+		{name: "key only", pem: first.keyPEM, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := ParseCACertificate(tt.pem)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pool == nil {
+				t.Fatal("expected a certificate pool")
+			}
+		})
+	}
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	now := time.Now()
+	ca := newTestCertificate(t, "CA", nil, now.Add(-48*time.Hour), now.Add(48*time.Hour))
+	valid := newTestCertificate(t, "valid", ca, now.Add(-time.Hour), now.Add(time.Hour))
+	other := newTestCertificate(t, "other", ca, now.Add(-time.Hour), now.Add(time.Hour))
+	expired := newTestCertificate(t, "expired", ca, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	notYetValid := newTestCertificate(t, "not yet valid", ca, now.Add(24*time.Hour), now.Add(48*time.Hour))
+
+	tests := []struct {
+		name        string
+		certificate string
+		key         string
+		wantError   string
+	}{
+		{name: "valid", certificate: valid.certPEM, key: valid.keyPEM},
+		{name: "mismatched key", certificate: valid.certPEM, key: other.keyPEM, wantError: "invalid client certificate or key: tls: private key does not match public key"},
+		{name: "expired", certificate: expired.certPEM, key: expired.keyPEM, wantError: `client certificate "CN=expired" expired at `},
+		{name: "not yet valid", certificate: notYetValid.certPEM, key: notYetValid.keyPEM, wantError: `client certificate "CN=not yet valid" is not valid before `},
+		{name: "not PEM", certificate: "not a certificate", key: valid.keyPEM, wantError: "invalid client certificate or key: "},
+		{name: "missing key", certificate: valid.certPEM, wantError: "invalid client certificate or key: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair, err := LoadClientCertificate(tt.certificate, tt.key)
+			if tt.wantError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantError) {
+					t.Fatalf("got error %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pair.Leaf == nil || pair.Leaf.Subject.CommonName != "valid" {
+				t.Fatalf("got leaf %v, want the valid certificate", pair.Leaf)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigMutualTLS(t *testing.T) {
+	now := time.Now()
+	ca := newTestCertificate(t, "CA", nil, now.Add(-time.Hour), now.Add(time.Hour))
+	clientCertificate := newTestCertificate(t, "terraform", ca, now.Add(-time.Hour), now.Add(time.Hour))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	// The refused handshakes are expected.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantError bool
+	}{
+		{
+			name:   "client certificate",
+			config: Config{CACertificate: serverCA, ClientCertificate: clientCertificate.certPEM, ClientKey: clientCertificate.keyPEM},
+		},
+		{
+			name:      "no client certificate",
+			config:    Config{CACertificate: serverCA},
+			wantError: true,
+		},
+		{
+			name:      "unknown server CA",
+			config:    Config{ClientCertificate: clientCertificate.certPEM, ClientKey: clientCertificate.keyPEM},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := newTLSConfig(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+			resp, err := httpClient.Get(server.URL)
+			if tt.wantError {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("expected the TLS handshake to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || string(body) != "terraform" {
+				t.Fatalf("got status %d and client %q, want 200 and terraform", resp.StatusCode, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	authTokenHeader = "X-SDS-AUTH-TOKEN"
+	loginPath       = "/login"
+	whoamiPath      = "/user/whoami"
+
+	// tokenCacheLifetime is how long a cached token is reused. ECS tokens are
+	// valid for 8 hours but expire after 2 hours of inactivity.
+	tokenCacheLifetime = time.Hour
+)
+
+// tokenCache stores the session tokens of several endpoint and user pairs in one file.
+type tokenCache struct {
+	path string
+	key  string
+}
+
+type tokenCacheEntry struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newTokenCache(path, endpoint, username string) *tokenCache {
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(endpoint, "/") + "\n" + username))
+	return &tokenCache{
+		path: path,
+		key:  hex.EncodeToString(sum[:]),
+	}
+}
+
+// load returns the cached token if it has not expired.
+func (c *tokenCache) load() (string, bool) {
+	entries, err := c.read()
+	if err != nil {
+		return "", false
+	}
+	entry, ok := entries[c.key]
+	if !ok || entry.Token == "" || time.Now().After(entry.ExpiresAt) {
+		return "", false
+	}
+	return entry.Token, true
+}
+
+// store saves the token, dropping the expired entries of the file.
+func (c *tokenCache) store(token string) error {
+	return c.update(func(entries map[string]tokenCacheEntry) {
+		entries[c.key] = tokenCacheEntry{
+			Token:     token,
+			ExpiresAt: time.Now().Add(tokenCacheLifetime),
+		}
+	})
+}
+
+// remove drops the token from the cache.
+func (c *tokenCache) remove() error {
+	return c.update(func(entries map[string]tokenCacheEntry) {
+		delete(entries, c.key)
+	})
+}
+
+func (c *tokenCache) read() (map[string]tokenCacheEntry, error) {
+	entries := map[string]tokenCacheEntry{}
+	content, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("invalid token cache file %s: %w", c.path, err)
+	}
+	return entries, nil
+}
+
+// update rewrites the cache file atomically so concurrent runs never read a partial file.
+func (c *tokenCache) update(change func(map[string]tokenCacheEntry)) error {
+	entries, err := c.read()
+	if err != nil {
+		// A corrupted cache is only a cache, start over.
+		entries = map[string]tokenCacheEntry{}
+	}
+
+	now := time.Now()
+	for key, entry := range entries {
+		if now.After(entry.ExpiresAt) {
+			delete(entries, key)
+		}
+	}
+	change(entries)
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".objectscale-token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// checkToken tells whether the management API accepts the token.
+func checkToken(httpClient *http.Client, endpoint, token string) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+whoamiPath, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(authTokenHeader, token)
+	req.Header.Set(RequestIDHeader, newRequestID())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %s while checking the auth token", resp.Status)
+	}
+}
+
+// whoami returns the name of the user the session of the client belongs to.
+func whoami(httpClient *http.Client, endpoint string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+whoamiPath, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(RequestIDHeader, newRequestID())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s while reading the current user", resp.Status)
+	}
+
+	var user struct {
+		CommonName string `json:"common_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("invalid current user: %w", err)
+	}
+	if user.CommonName == "" {
+		return "", errors.New("the cluster did not return the name of the current user")
+	}
+	return user.CommonName, nil
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenCache(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare writes the cache file before the token of https://a:4443 and root is loaded.
+		prepare   func(t *testing.T, path string)
+		wantToken string
+	}{
+		{
+			name:    "no file",
+			prepare: func(t *testing.T, path string) {},
+		},
+		{
+			name: "stored token",
+			prepare: func(t *testing.T, path string) {
+				if err := newTokenCache(path, "https://a:4443/", "root").store("t1"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantToken: "t1",
+		},
+		{
+			name: "token of another user",
+			prepare: func(t *testing.T, path string) {
+				if err := newTokenCache(path, "https://a:4443", "admin").store("t1"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "token of another endpoint",
+			prepare: func(t *testing.T, path string) {
+				if err := newTokenCache(path, "https://b:4443", "root").store("t1"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "removed token",
+			prepare: func(t *testing.T, path string) {
+				cache := newTokenCache(path, "https://a:4443", "root")
+				if err := cache.store("t1"); err != nil {
+					t.Fatal(err)
+				}
+				if err := cache.remove(); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "expired token",
+			prepare: func(t *testing.T, path string) {
+				writeTokenCache(t, path, newTokenCache(path, "https://a:4443", "root").key, time.Now().Add(-time.Minute))
+			},
+		},
+		{
+			name: "corrupted file",
+			prepare: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens.json")
+			test.prepare(t, path)
+
+			token, ok := newTokenCache(path, "https://a:4443", "root").load()
+			if token != test.wantToken || ok != (test.wantToken != "") {
+				t.Errorf("load() = %q, %v, want %q", token, ok, test.wantToken)
+			}
+		})
+	}
+}
+
+func TestTokenCacheStoreDropsExpiredEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "tokens.json")
+	writeTokenCache(t, path, "expired", time.Now().Add(-time.Minute))
+
+	if err := newTokenCache(path, "https://a:4443", "root").store("t1"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := newTokenCache(path, "", "").read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entries["expired"]; ok || len(entries) != 1 {
+		t.Errorf("got entries %v, want only the stored token", entries)
+	}
+}
+
+func writeTokenCache(t *testing.T, path, key string, expiresAt time.Time) {
+	t.Helper()
+	content, err := json.Marshal(map[string]tokenCacheEntry{key: {Token: "old", ExpiresAt: expiresAt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the spans of the provider.
+const TracerName = "terraform-provider-objectscale"
+
+// Attributes of the spans of the provider.
+const (
+	AttributeResourceType = attribute.Key("objectscale.resource_type")
+	AttributeNamespace    = attribute.Key("objectscale.namespace")
+	AttributeStatusCode   = attribute.Key("http.response.status_code")
+	AttributeRequestID    = attribute.Key("objectscale.request_id")
+	AttributeCacheHit     = attribute.Key("objectscale.cache_hit")
+)
+
+// startSpan opens the span of a management API call. The spans go nowhere
+// unless the provider installed a tracer provider.
+func startSpan(ctx context.Context, operation, namespace string) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(TracerName).Start(ctx, "objectscale."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	if namespace != "" {
+		span.SetAttributes(AttributeNamespace.String(namespace))
+	}
+	if id := contextRequestID(ctx); id != "" {
+		span.SetAttributes(AttributeRequestID.String(id))
+	}
+	return ctx, span
+}
+
+// endSpan records the outcome of the call and ends its span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		if sent, _ := RequestIDs(err); sent != "" {
+			span.SetAttributes(AttributeRequestID.String(sent))
+		}
+		if apiErr, ok := AsAPIError(err); ok && apiErr.StatusCode != 0 {
+			span.SetAttributes(AttributeStatusCode.Int(apiErr.StatusCode))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startHTTPSpan opens the span of one HTTP request of a management API call.
+func startHTTPSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, "HTTP "+method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.request.method", method), attribute.String("url.path", path)))
+}
@@ -0,0 +1,66 @@
+package client
+
+// The types of the management API. Their tf tags match the tfsdk tags of the
+// models, so helper.CopyFields copies them to and from the Terraform state, and
+// their json tags match the REST representation used by the native backend.
+
+// Link is the hyperlink to the details of a resource.
+type Link struct {
+	Rel  string `tf:"rel" json:"rel"`
+	Href string `tf:"href" json:"href"`
+}
+
+// Attribute is a key-value pair of a user mapping.
+type Attribute struct {
+	Key   string   `tf:"key" json:"key"`
+	Value []string `tf:"value" json:"value"`
+}
+
+// UserMapping maps the users of an identity provider domain to a namespace.
+type UserMapping struct {
+	Domain     string      `tf:"domain" json:"domain"`
+	Attributes []Attribute `tf:"attributes" json:"attributes"`
+	Groups     []string    `tf:"groups" json:"groups"`
+}
+
+// RetentionClass is a named retention period, in seconds.
+type RetentionClass struct {
+	Name   string `tf:"name" json:"name"`
+	Period int64  `tf:"period" json:"period"`
+}
+
+// RetentionClasses are the retention classes of a namespace.
+type RetentionClasses struct {
+	RetentionClass []RetentionClass `tf:"retention_class" json:"retention_class"`
+}
+
+// Namespace is a tenant of the cluster.
+type Namespace struct {
+	Name                         string           `tf:"name" json:"name"`
+	Id                           string           `tf:"id" json:"id"`
+	Global                       bool             `tf:"global" json:"global"`
+	Remote                       bool             `tf:"remote" json:"remote"`
+	Link                         Link             `tf:"link" json:"link"`
+	CreationTime                 int64            `tf:"creation_time" json:"creation_time"`
+	Inactive                     bool             `tf:"inactive" json:"inactive"`
+	Internal                     bool             `tf:"internal" json:"internal"`
+	DefaultDataServicesVpool     string           `tf:"default_data_services_vpool" json:"default_data_services_vpool"`
+	AllowedVpoolsList            []string         `tf:"allowed_vpools_list" json:"allowed_vpools_list"`
+	DisallowedVpoolsList         []string         `tf:"disallowed_vpools_list" json:"disallowed_vpools_list"`
+	NamespaceAdmins              string           `tf:"namespace_admins" json:"namespace_admins"`
+	UserMapping                  []UserMapping    `tf:"user_mapping" json:"user_mapping"`
+	IsEncryptionEnabled          bool             `tf:"is_encryption_enabled" json:"is_encryption_enabled"`
+	DefaultBucketBlockSize       int64            `tf:"default_bucket_block_size" json:"default_bucket_block_size"`
+	ExternalGroupAdmins          string           `tf:"external_group_admins" json:"external_group_admins"`
+	IsStaleAllowed               bool             `tf:"is_stale_allowed" json:"is_stale_allowed"`
+	IsObjectLockWithAdoAllowed   bool             `tf:"is_object_lock_with_ado_allowed" json:"is_object_lock_with_ado_allowed"`
+	IsComplianceEnabled          bool             `tf:"is_compliance_enabled" json:"is_compliance_enabled"`
+	NotificationSize             int64            `tf:"notification_size" json:"notification_size"`
+	BlockSize                    int64            `tf:"block_size" json:"block_size"`
+	NotificationSizeInCount      int64            `tf:"notification_size_in_count" json:"notification_size_in_count"`
+	BlockSizeInCount             int64            `tf:"block_size_in_count" json:"block_size_in_count"`
+	DefaultAuditDeleteExpiration int64            `tf:"default_audit_delete_expiration" json:"default_audit_delete_expiration"`
+	RetentionClasses             RetentionClasses `tf:"retention_classes" json:"retention_classes"`
+	RootUserName                 string           `tf:"root_user_name" json:"root_user_name"`
+	RootUserPassword             string           `tf:"root_user_password" json:"root_user_password"`
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const nodesPath = "/vdc/nodes"
+
+// The first releases supporting the newer namespace attributes.
+var (
+	VersionObjectLockWithAdo     = Version{Major: 3, Minor: 8}
+	VersionAuditDeleteExpiration = Version{Major: 3, Minor: 7}
+)
+
+// Version is the release of ECS or ObjectScale, ex. 3.8.0. The zero value means unknown.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses the leading numbers of a version such as "3.8.0.2.137384.4b95b8a7fd".
+func ParseVersion(value string) (Version, error) {
+	var numbers [3]int
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(value), "v"), ".", 4)
+	if len(parts) < 2 {
+		return Version{}, fmt.Errorf("invalid version %q", value)
+	}
+	for i := 0; i < len(numbers) && i < len(parts); i++ {
+		number, err := strconv.Atoi(parts[i])
+		if err != nil || number < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", value)
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// Known reports whether the version was detected.
+func (v Version) Known() bool {
+	return v != Version{}
+}
+
+// AtLeast reports whether v is the same release as min or a later one.
+func (v Version) AtLeast(min Version) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Supports reports whether the release is min or a later one. An unknown release
+// is assumed to support everything, the cluster then has the last word.
+func (v Version) Supports(min Version) bool {
+	return !v.Known() || v.AtLeast(min)
+}
+
+// Version returns the release of the cluster, unknown if it could not be detected.
+func (c *Client) Version() Version {
+	return c.version
+}
+
+// detectVersion returns the oldest release run by the nodes of the cluster,
+// which is the one every call can rely on during a rolling upgrade.
+func detectVersion(httpClient *http.Client, endpoint string) (Version, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+nodesPath, nil)
+	if err != nil {
+		return Version{}, err
+	}
+	req.Header.Set(RequestIDHeader, newRequestID())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Version{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Version{}, fmt.Errorf("unexpected status %s while listing the nodes", resp.Status)
+	}
+
+	var nodes struct {
+		Node []struct {
+			Version string `json:"version"`
+		} `json:"node"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
+		return Version{}, fmt.Errorf("unable to decode the nodes: %w", err)
+	}
+
+	var oldest Version
+	for _, node := range nodes.Node {
+		version, err := ParseVersion(node.Version)
+		if err != nil {
+			return Version{}, err
+		}
+		if !oldest.Known() || !version.AtLeast(oldest) {
+			oldest = version
+		}
+	}
+	if !oldest.Known() {
+		return Version{}, fmt.Errorf("no node version was returned by %s", nodesPath)
+	}
+	return oldest, nil
+}
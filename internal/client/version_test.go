@@ -0,0 +1,85 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      Version
+		wantError bool
+	}{
+		{value: "3.8.0.2.137384.4b95b8a7fd", want: Version{Major: 3, Minor: 8}},
+		{value: "v4.0.1", want: Version{Major: 4, Minor: 0, Patch: 1}},
+		{value: "3.7", want: Version{Major: 3, Minor: 7}},
+		{value: "3", wantError: true},
+		{value: "3.x.0", wantError: true},
+		{value: "", wantError: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.value)
+		if (err != nil) != tt.wantError || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %s, %v, want %s, error %v", tt.value, got, err, tt.want, tt.wantError)
+		}
+	}
+}
+
+func TestVersionSupports(t *testing.T) {
+	tests := []struct {
+		version Version
+		min     Version
+		want    bool
+	}{
+		{version: Version{}, min: VersionObjectLockWithAdo, want: true},
+		{version: Version{Major: 3, Minor: 8}, min: VersionObjectLockWithAdo, want: true},
+		{version: Version{Major: 3, Minor: 8, Patch: 1}, min: VersionObjectLockWithAdo, want: true},
+		{version: Version{Major: 4}, min: VersionObjectLockWithAdo, want: true},
+		{version: Version{Major: 3, Minor: 7, Patch: 9}, min: VersionObjectLockWithAdo, want: false},
+		{version: Version{Major: 2, Minor: 9}, min: VersionAuditDeleteExpiration, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.version.Supports(tt.min); got != tt.want {
+			t.Errorf("%s.Supports(%s) = %v, want %v", tt.version, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestNamespaceBodyLeavesOutUnsupportedAttributes(t *testing.T) {
+	namespace := &Namespace{Name: "ns1", IsObjectLockWithAdoAllowed: true, DefaultAuditDeleteExpiration: 7}
+	tests := []struct {
+		version       Version
+		wantObjectADO bool
+		wantAudit     bool
+	}{
+		{version: Version{}, wantObjectADO: true, wantAudit: true},
+		{version: Version{Major: 3, Minor: 8}, wantObjectADO: true, wantAudit: true},
+		{version: Version{Major: 3, Minor: 7}, wantAudit: true},
+		{version: Version{Major: 3, Minor: 6}},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(newNamespaceBody(namespace, tt.version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := body["is_object_lock_with_ado_allowed"]; ok != tt.wantObjectADO {
+			t.Errorf("version %s: is_object_lock_with_ado_allowed sent %v, want %v", tt.version, ok, tt.wantObjectADO)
+		}
+		if _, ok := body["default_audit_delete_expiration"]; ok != tt.wantAudit {
+			t.Errorf("version %s: default_audit_delete_expiration sent %v, want %v", tt.version, ok, tt.wantAudit)
+		}
+	}
+
+	// A supported attribute is sent even with its default value.
+	data, _ := json.Marshal(newNamespaceBody(&Namespace{Name: "ns1"}, Version{Major: 3, Minor: 8}))
+	var body map[string]interface{}
+	_ = json.Unmarshal(data, &body)
+	if body["is_object_lock_with_ado_allowed"] != false || body["default_audit_delete_expiration"] != float64(0) {
+		t.Errorf("got body %s, want the default values sent", data)
+	}
+}
@@ -18,9 +18,10 @@ import (
 // CopyFields copy the source of a struct to destination of struct with terraform types.
 // Unsigned integers are not properly handled.
 func CopyFields(ctx context.Context, source, destination interface{}) error {
+	// The values are not logged, they may hold secrets such as the root user password.
 	tflog.Debug(ctx, "Copy fields", map[string]interface{}{
-		"source":      source,
-		"destination": destination,
+		"source":      fmt.Sprintf("%T", source),
+		"destination": fmt.Sprintf("%T", destination),
 	})
 	sourceValue := reflect.ValueOf(source)
 	destinationValue := reflect.ValueOf(destination)
@@ -64,9 +65,11 @@ func CopyFields(ctx context.Context, source, destination interface{}) error {
 		destinationField := getFieldByTfTag(destinationValue.Elem(), sourceFieldTag)
 		if destinationField.IsValid() && destinationField.CanSet() {
 
-			tflog.Debug(ctx, "debugging source field", map[string]interface{}{
-				"sourceField Interface": sourceField.Interface(),
-			})
+			if !isSensitiveField(sourceFieldTag) {
+				tflog.Debug(ctx, "debugging source field", map[string]interface{}{
+					"sourceField Interface": sourceField.Interface(),
+				})
+			}
 			// Convert the source value to the type of the destination field dynamically
 			var destinationFieldValue attr.Value
 
@@ -152,6 +155,16 @@ func CopyFields(ctx context.Context, source, destination interface{}) error {
 	return nil
 }
 
+// isSensitiveField tells whether the value of the field must not be logged.
+func isSensitiveField(tag string) bool {
+	for _, sensitive := range []string{"password", "token", "secret"} {
+		if strings.Contains(tag, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
 func getFieldJSONTag(sourceValue reflect.Value, i int) string {
 	sourceFieldTag := sourceValue.Type().Field(i).Tag.Get("tf")
 	//sourceFieldTag = strings.TrimSuffix(sourceFieldTag, ",omitempty")
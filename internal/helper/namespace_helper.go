@@ -3,14 +3,13 @@ package helper
 import (
 	"context"
 	"fmt"
+	"terraform-provider-objectscale/internal/client"
 	"terraform-provider-objectscale/internal/models"
-
-	objectscale "github.com/vangork/objectscale-client/golang/pkg"
 )
 
-func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceModel) (*objectscale.Namespace, error) {
-	retentionClasses := &objectscale.RetentionClasses{
-		RetentionClass: []objectscale.RetentionClass{},
+func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceModel) (*client.Namespace, error) {
+	retentionClasses := &client.RetentionClasses{
+		RetentionClass: []client.RetentionClass{},
 	}
 	if !plan.RetentionClasses.IsUnknown() {
 		if err := assignObjectToField(ctx, plan.RetentionClasses, retentionClasses); err != nil {
@@ -18,7 +17,7 @@ func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceM
 		}
 	}
 
-	userMapping := []objectscale.UserMapping{}
+	userMapping := []client.UserMapping{}
 
 	if !plan.UserMapping.IsNull() && !plan.UserMapping.IsUnknown() {
 		var userMappingList []models.UserMappingResource
@@ -28,7 +27,7 @@ func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceM
 		}
 
 		for _, userMappingItem := range userMappingList {
-			item := &objectscale.UserMapping{}
+			item := &client.UserMapping{}
 
 			if err := readFromState(ctx, userMappingItem, item); err != nil {
 				return nil, fmt.Errorf("error parsing user mapping: %v", err)
@@ -40,9 +39,9 @@ func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceM
 				return nil, fmt.Errorf("error parsing attribute list")
 			}
 
-			attributes := []objectscale.Attribute{}
+			attributes := []client.Attribute{}
 			for _, attributeItem := range attributeList {
-				item := &objectscale.Attribute{}
+				item := &client.Attribute{}
 
 				if err := readFromState(ctx, attributeItem, item); err != nil {
 					return nil, fmt.Errorf("error parsing attribute: %v", err)
@@ -55,7 +54,7 @@ func BuildNamespaceFromPlan(ctx context.Context, plan *models.NamespaceResourceM
 		}
 	}
 
-	namespace := &objectscale.Namespace{
+	namespace := &client.Namespace{
 		Name:                     plan.Name.ValueString(),
 		DefaultDataServicesVpool: plan.DefaultDataServicesVpool.ValueString(),
 
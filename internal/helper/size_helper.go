@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// QuotaUnit is the unit of the namespace quotas, block_size and notification_size.
+const QuotaUnit = "GB"
+
+// sizeUnits are the size units with their number of bytes, by upper case name.
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+// unitBytes returns the number of bytes of a unit, matched case insensitively.
+func unitBytes(unit string) (float64, error) {
+	bytes, ok := sizeUnits[strings.ToUpper(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q, expected one of B, KB, MB, GB, TB, PB, KiB, MiB, GiB, TiB or PiB", unit)
+	}
+	return bytes, nil
+}
+
+// ConvertSize converts a size between two units, ex. from GiB to GB.
+func ConvertSize(value float64, from, to string) (float64, error) {
+	fromBytes, err := unitBytes(from)
+	if err != nil {
+		return 0, err
+	}
+	toBytes, err := unitBytes(to)
+	if err != nil {
+		return 0, err
+	}
+	return value * fromBytes / toBytes, nil
+}
+
+// QuotaSize converts a size to whole quota units, rounded up so the quota is never
+// smaller than the given size.
+func QuotaSize(value float64, unit string) (int64, error) {
+	if value < 0 || math.IsNaN(value) {
+		return 0, fmt.Errorf("the size %v must be a number, zero or more", value)
+	}
+	size, err := ConvertSize(value, unit, QuotaUnit)
+	if err != nil {
+		return 0, err
+	}
+	// Drop the floating point noise of the conversion before rounding up, relative to
+	// the size so a size of a few bytes still takes one quota unit.
+	if rounded := math.Round(size); math.Abs(size-rounded) <= size*1e-12 {
+		size = rounded
+	} else {
+		size = math.Ceil(size)
+	}
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("the size %v %s is too large for a quota", value, unit)
+	}
+	return int64(size), nil
+}
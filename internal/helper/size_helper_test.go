@@ -0,0 +1,76 @@
+package helper
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvertSize(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{value: 1, from: "GiB", to: "B", want: 1 << 30},
+		{value: 1, from: "GiB", to: "MiB", want: 1024},
+		{value: 1500, from: "mb", to: "GB", want: 1.5},
+		{value: 2, from: " TB ", to: "GB", want: 2000},
+		{value: 1, from: "PiB", to: "TiB", want: 1024},
+		{value: 0, from: "KB", to: "B", want: 0},
+		{value: -1, from: "KB", to: "B", want: -1000},
+		{value: 1, from: "GiB", to: "GiB", want: 1},
+		{value: 1, from: "gigabytes", to: "GB", wantErr: true},
+		{value: 1, from: "GB", to: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ConvertSize(test.value, test.from, test.to)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ConvertSize(%v, %q, %q): got error %v, want error %v", test.value, test.from, test.to, err, test.wantErr)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-9*math.Max(1, math.Abs(test.want)) {
+			t.Errorf("ConvertSize(%v, %q, %q) = %v, want %v", test.value, test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestQuotaSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   float64
+		unit    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "whole quota units", value: 5, unit: "GB", want: 5},
+		{name: "zero", value: 0, unit: "TiB", want: 0},
+		{name: "rounded up", value: 1, unit: "GiB", want: 2},
+		{name: "fraction rounded up", value: 1.2, unit: "GB", want: 2},
+		{name: "smallest size rounded up", value: 1, unit: "B", want: 1},
+		{name: "conversion noise is not rounded up", value: 0.3, unit: "TB", want: 300},
+		{name: "exact decimal", value: 1500, unit: "MB", want: 2},
+		{name: "binary terabytes", value: 1, unit: "TiB", want: 1100},
+		{name: "negative", value: -1, unit: "GB", wantErr: true},
+		{name: "negative fraction", value: -0.5, unit: "B", wantErr: true},
+		{name: "not a number", value: math.NaN(), unit: "GB", wantErr: true},
+		{name: "overflow", value: 1e13, unit: "PB", wantErr: true},
+		{name: "large quota", value: 1e9, unit: "PB", want: 1e15},
+		{name: "infinite", value: math.Inf(1), unit: "GB", wantErr: true},
+		{name: "largest float", value: math.MaxFloat64, unit: "B", wantErr: true},
+		{name: "unknown unit", value: 1, unit: "GiBs", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := QuotaSize(test.value, test.unit)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("QuotaSize(%v, %q) = %d, want %d", test.value, test.unit, got, test.want)
+			}
+		})
+	}
+}
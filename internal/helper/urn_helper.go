@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// urnPrefix starts the identifiers of the ObjectScale objects,
+// ex. urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global.
+const urnPrefix = "urn:storageos:"
+
+var (
+	urnTypePattern  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	urnScopePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]*$`)
+)
+
+// URN is an ObjectScale object identifier split in its parts.
+type URN struct {
+	// Type is the kind of object, ex. ReplicationGroupInfo.
+	Type string `tfsdk:"type"`
+	UUID string `tfsdk:"uuid"`
+	// Scope is the VDC owning the object, or global. It is empty for the objects of the local VDC.
+	Scope string `tfsdk:"scope"`
+}
+
+// ParseURN splits an ObjectScale URN in its type, UUID and scope.
+func ParseURN(urn string) (URN, error) {
+	if !strings.HasPrefix(urn, urnPrefix) {
+		return URN{}, fmt.Errorf("%q is not an objectscale URN, it must start with %q", urn, urnPrefix)
+	}
+	parts := strings.Split(strings.TrimPrefix(urn, urnPrefix), ":")
+	if len(parts) != 3 {
+		return URN{}, fmt.Errorf("%q is not an objectscale URN, it must be of the form %s<type>:<uuid>:<scope>", urn, urnPrefix)
+	}
+
+	parsed := URN{Type: parts[0], UUID: parts[1], Scope: parts[2]}
+	if err := parsed.validate(); err != nil {
+		return URN{}, fmt.Errorf("invalid objectscale URN %q: %v", urn, err)
+	}
+	return parsed, nil
+}
+
+// BuildURN joins a type, a UUID and a scope in an ObjectScale URN.
+func BuildURN(urnType, uuid, scope string) (string, error) {
+	urn := URN{Type: urnType, UUID: uuid, Scope: scope}
+	if err := urn.validate(); err != nil {
+		return "", err
+	}
+	return urn.String(), nil
+}
+
+// String returns the URN.
+func (u URN) String() string {
+	return urnPrefix + u.Type + ":" + u.UUID + ":" + u.Scope
+}
+
+func (u URN) validate() error {
+	if !urnTypePattern.MatchString(u.Type) {
+		return fmt.Errorf("the type %q must be alphanumeric and start with a letter", u.Type)
+	}
+	if !uuidPattern.MatchString(u.UUID) {
+		return fmt.Errorf("%q is not a UUID", u.UUID)
+	}
+	if !urnScopePattern.MatchString(u.Scope) {
+		return fmt.Errorf("the scope %q must only contain letters, digits, '_', '.' and '-'", u.Scope)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseURN(t *testing.T) {
+	tests := []struct {
+		urn     string
+		want    URN
+		wantErr string
+	}{
+		{
+			urn:  "urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global",
+			want: URN{Type: "ReplicationGroupInfo", UUID: "0e953ad1-94a5-4eb1-825a-d58d29e85434", Scope: "global"},
+		},
+		{
+			urn:  "urn:storageos:VirtualDataCenterData:0E953AD1-94A5-4EB1-825A-D58D29E85434:",
+			want: URN{Type: "VirtualDataCenterData", UUID: "0E953AD1-94A5-4EB1-825A-D58D29E85434"},
+		},
+		{
+			urn:  "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:vdc_1.site-a",
+			want: URN{Type: "Vpool", UUID: "0e953ad1-94a5-4eb1-825a-d58d29e85434", Scope: "vdc_1.site-a"},
+		},
+		{urn: "", wantErr: "must start with"},
+		{urn: "urn:other:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:global", wantErr: "must start with"},
+		{urn: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434", wantErr: "must be of the form"},
+		{urn: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:global:extra", wantErr: "must be of the form"},
+		{urn: "urn:storageos::0e953ad1-94a5-4eb1-825a-d58d29e85434:global", wantErr: "must be alphanumeric"},
+		{urn: "urn:storageos:1Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:global", wantErr: "must be alphanumeric"},
+		{urn: "urn:storageos:Vpool::global", wantErr: "is not a UUID"},
+		{urn: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a:global", wantErr: "is not a UUID"},
+		{urn: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e8543z:global", wantErr: "is not a UUID"},
+		{urn: "urn:storageos:Vpool:0e953ad194a54eb1825ad58d29e85434:global", wantErr: "is not a UUID"},
+		{urn: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:glo bal", wantErr: "the scope"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.urn, func(t *testing.T) {
+			got, err := ParseURN(test.urn)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			if got.String() != test.urn {
+				t.Errorf("String() = %q, want the parsed URN", got.String())
+			}
+		})
+	}
+}
+
+func TestBuildURN(t *testing.T) {
+	tests := []struct {
+		name                 string
+		urnType, uuid, scope string
+		want                 string
+		wantErr              bool
+	}{
+		{
+			name:    "global",
+			urnType: "ReplicationGroupInfo", uuid: "0e953ad1-94a5-4eb1-825a-d58d29e85434", scope: "global",
+			want: "urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global",
+		},
+		{
+			name:    "empty scope",
+			urnType: "Vpool", uuid: "0e953ad1-94a5-4eb1-825a-d58d29e85434",
+			want: "urn:storageos:Vpool:0e953ad1-94a5-4eb1-825a-d58d29e85434:",
+		},
+		{name: "empty type", uuid: "0e953ad1-94a5-4eb1-825a-d58d29e85434", wantErr: true},
+		{name: "type with a colon", urnType: "Vpool:x", uuid: "0e953ad1-94a5-4eb1-825a-d58d29e85434", wantErr: true},
+		{name: "empty uuid", urnType: "Vpool", wantErr: true},
+		{name: "bad uuid", urnType: "Vpool", uuid: "not-a-uuid", wantErr: true},
+		{name: "scope with a colon", urnType: "Vpool", uuid: "0e953ad1-94a5-4eb1-825a-d58d29e85434", scope: "a:b", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := BuildURN(test.urnType, test.uuid, test.scope)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			if err == nil {
+				if _, err := ParseURN(got); err != nil {
+					t.Errorf("the built URN does not parse: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type NamespaceDatasourceModel struct {
 	ID         types.String      `tfsdk:"id"`
@@ -64,6 +67,14 @@ type NamespaceEntity struct {
 	RootUserPassword types.String `tfsdk:"root_user_password"`
 }
 
+type NamespaceResourceEntity struct {
+	NamespaceEntity
+	// Endpoint the calls of the resource are sent to instead of the provider endpoint
+	Endpoint types.String `tfsdk:"endpoint"`
+	// Timeouts of the create, read, update and delete operations
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
 type TenancyLink struct {
 	//
 	Rel types.String `tfsdk:"rel"`
@@ -154,6 +165,10 @@ type NamespaceResourceModel struct {
 	RootUserName types.String `tfsdk:"root_user_name"`
 	// root user password.
 	RootUserPassword types.String `tfsdk:"root_user_password"`
+	// Endpoint the calls of the resource are sent to instead of the provider endpoint
+	Endpoint types.String `tfsdk:"endpoint"`
+	// Timeouts of the create, read, update and delete operations
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type UserMappingResource struct {
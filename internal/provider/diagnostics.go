@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-objectscale/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// addOperationError reports a failed management API call, telling apart the
+// operations cut off by their timeout and the ones refused in read-only mode.
+func addOperationError(diags *diag.Diagnostics, action, operation string, timeout time.Duration, err error) {
+	if errors.Is(err, client.ErrReadOnly) {
+		diags.AddError(
+			fmt.Sprintf("Error %s", action),
+			fmt.Sprintf("The %s operation was refused: the provider is configured with `read_only = true`, "+
+				"which only allows reading the cluster. Use a provider configuration without read_only to apply changes.", operation),
+		)
+		return
+	}
+	if errors.Is(err, client.ErrProtectedNamespace) {
+		addProtectedNamespaceError(diags, err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		diags.AddError(
+			fmt.Sprintf("Timeout %s", action),
+			fmt.Sprintf("The %s operation was cut off after %s. "+
+				"The change may still complete on the cluster, increase `timeouts.%s` if the cluster is busy.",
+				operation, timeout, operation)+requestIDDetail(err),
+		)
+		return
+	}
+	diags.AddError(errorSummary(fmt.Sprintf("Error %s", action), err), errorDetail(err))
+}
+
+// errorHints are the remediations of the failures reported by the management API.
+var errorHints = map[client.ErrorKind]string{
+	client.ErrorKindNotFound:     "The object does not exist on the cluster, it may have been deleted outside of Terraform.",
+	client.ErrorKindConflict:     "An object with the same name already exists, import it with `terraform import` or choose another name.",
+	client.ErrorKindUnauthorized: "The cluster rejected the login, check the username and password or the auth_token of the provider.",
+	client.ErrorKindForbidden:    "The user lacks the permission, namespace operations need the System Administrator role.",
+	client.ErrorKindValidation:   "The cluster rejected a value of the configuration, ex. an unknown replication group identifier.",
+	client.ErrorKindThrottled:    "The cluster throttled the calls, lower max_concurrent_requests or try again later.",
+	client.ErrorKindUnavailable:  "The cluster could not serve the call, try again later or raise max_retries.",
+}
+
+// errorSummary appends the kind of failure to the summary of a management API error.
+func errorSummary(summary string, err error) string {
+	if apiErr, ok := client.AsAPIError(err); ok && apiErr.Kind() != client.ErrorKindUnknown {
+		return fmt.Sprintf("%s: %s", summary, apiErr.Kind())
+	}
+	return summary
+}
+
+// errorDetail describes a management API error, with a remediation hint when the kind of
+// failure is known and the request IDs to look for in the audit log of the cluster.
+func errorDetail(err error) string {
+	apiErr, ok := client.AsAPIError(err)
+	if !ok {
+		return err.Error() + requestIDDetail(err)
+	}
+	detail := err.Error()
+	if hint, ok := errorHints[apiErr.Kind()]; ok {
+		detail += "\n\n" + hint
+	}
+	if apiErr.Retryable {
+		detail += "\n\nThe cluster reports the call may succeed if retried."
+	}
+	return detail + requestIDDetail(err)
+}
+
+// requestIDDetail names the request IDs of a failed management call, if any.
+func requestIDDetail(err error) string {
+	sent, server := client.RequestIDs(err)
+	switch {
+	case sent != "" && server != "":
+		return fmt.Sprintf("\n\nRequest ID: %s (cluster request ID: %s)", sent, server)
+	case sent != "":
+		return fmt.Sprintf("\n\nRequest ID: %s", sent)
+	case server != "":
+		return fmt.Sprintf("\n\nCluster request ID: %s", server)
+	}
+	return ""
+}
+
+// addProtectedNamespaceError reports the refusal to delete a protected namespace.
+func addProtectedNamespaceError(diags *diag.Diagnostics, err error) {
+	diags.AddError(
+		"Protected namespace",
+		fmt.Sprintf("%s. The namespace is listed in the protected_namespaces of the provider. "+
+			"To delete it anyway, set the %s environment variable to its name for the run deleting it.", err.Error(), envProtectedNamespacesOverride),
+	)
+}
+
+// nullTimeouts returns an unset timeouts block, as found in the state of an imported resource.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
+	}
+}
@@ -21,7 +21,7 @@ func NewNamespaceDataSource() datasource.DataSource {
 }
 
 type NamespaceDataSource struct {
-	client *client.Client
+	client client.ManagementAPI
 }
 
 func (d *NamespaceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -264,12 +264,12 @@ func (d *NamespaceDataSource) Configure(ctx context.Context, req datasource.Conf
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	client, ok := req.ProviderData.(client.ManagementAPI)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.ManagementAPI, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -287,11 +287,14 @@ func (d *NamespaceDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
-	namespaces, err := d.client.ManagementClient.ListNamespaces("")
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "ReadDataSource", "")
+	defer func() { endTrace(resp.Diagnostics) }()
+
+	namespaces, err := d.client.ListNamespaces(ctx, "")
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error getting the list of namespaces",
-			err.Error(),
+			errorSummary("Error getting the list of namespaces", err),
+			errorDetail(err),
 		)
 		return
 	}
@@ -20,11 +20,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-objectscale/internal/client"
 	"terraform-provider-objectscale/internal/helper"
 	"terraform-provider-objectscale/internal/models"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -32,13 +38,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// defaultNamespaceTimeout bounds every namespace operation without a configured timeout.
+const defaultNamespaceTimeout = 20 * time.Minute
+
+// namespaceTypeName is the type name of the namespace resource and data source, as traced.
+const namespaceTypeName = "objectscale_namespace"
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &NamespaceResource{}
 var _ resource.ResourceWithImportState = &NamespaceResource{}
+var _ resource.ResourceWithModifyPlan = &NamespaceResource{}
 
 func NewNamespaceResource() resource.Resource {
 	return &NamespaceResource{}
@@ -46,7 +60,7 @@ func NewNamespaceResource() resource.Resource {
 
 // NamespaceResource defines the resource implementation.
 type NamespaceResource struct {
-	client *client.Client
+	client client.ManagementAPI
 }
 
 func (r *NamespaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -320,6 +334,22 @@ func (r *NamespaceResource) Schema(ctx context.Context, req resource.SchemaReque
 				MarkdownDescription: "root user password.",
 				Computed:            true,
 			},
+			"endpoint": schema.StringAttribute{
+				Description:         "API endpoint the calls of this namespace are sent to instead of the provider endpoint, ex. the VDC owning it in a geo-federation. It is logged in to with the provider credentials. Set on import with an ID of the form <endpoint>,<id>. Updatable.",
+				MarkdownDescription: "API endpoint the calls of this namespace are sent to instead of the provider endpoint, ex. the VDC owning it in a geo-federation. It is logged in to with the provider credentials. Set on import with an ID of the form `<endpoint>,<id>`. Updatable.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -330,12 +360,12 @@ func (r *NamespaceResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	client, ok := req.ProviderData.(client.ManagementAPI)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected client.ManagementAPI, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -344,6 +374,94 @@ func (r *NamespaceResource) Configure(ctx context.Context, req resource.Configur
 	r.client = client
 }
 
+// api returns the ManagementAPI of the endpoint override of the namespace,
+// the one of the provider without it.
+func (r *NamespaceResource) api(endpoint types.String) (client.ManagementAPI, error) {
+	if endpoint.IsNull() || endpoint.IsUnknown() || endpoint.ValueString() == "" {
+		return r.client, nil
+	}
+	endpoints, ok := r.client.(client.EndpointAPI)
+	if !ok {
+		return nil, fmt.Errorf("the provider client cannot send calls to another endpoint")
+	}
+	return endpoints.ForEndpoint(endpoint.ValueString())
+}
+
+// addEndpointError reports the failure to connect to the endpoint override.
+func addEndpointError(diags *diag.Diagnostics, endpoint types.String, err error) {
+	diags.AddAttributeError(
+		path.Root("endpoint"),
+		"Unable to connect to the namespace endpoint",
+		fmt.Sprintf("Unable to create the objectscale client of %s with the provider credentials: %s", endpoint.ValueString(), err.Error()),
+	)
+}
+
+// namespaceAttributeVersions are the first releases supporting the newer namespace attributes,
+// with the default of the attribute, which the older releases are not sent.
+var namespaceAttributeVersions = []struct {
+	name         string
+	version      client.Version
+	defaultValue attr.Value
+}{
+	{name: "is_object_lock_with_ado_allowed", version: client.VersionObjectLockWithAdo, defaultValue: types.BoolValue(false)},
+	{name: "default_audit_delete_expiration", version: client.VersionAuditDeleteExpiration, defaultValue: types.Int64Value(0)},
+}
+
+// ModifyPlan refuses at plan time the destruction of a protected namespace
+// and the attributes unsupported by the release of the cluster.
+func (r *NamespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		if req.State.Raw.IsNull() {
+			return
+		}
+		// The protection only needs the identifier, which is also the name.
+		var id types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		policy, ok := r.client.(client.ClusterPolicy)
+		if !ok {
+			return
+		}
+		if err := policy.CheckNamespaceDeletion(id.ValueString()); err != nil {
+			addProtectedNamespaceError(&resp.Diagnostics, err)
+		}
+		return
+	}
+
+	// The attributes are checked against the release of the endpoint the namespace is written to.
+	var endpoint types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("endpoint"), &endpoint)...)
+	api, err := r.api(endpoint)
+	if err != nil {
+		addEndpointError(&resp.Diagnostics, endpoint, err)
+		return
+	}
+	policy, ok := api.(client.ClusterPolicy)
+	if !ok {
+		return
+	}
+
+	for _, gated := range namespaceAttributeVersions {
+		var value attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(gated.name), &value)...)
+		if value == nil || value.IsNull() || value.IsUnknown() || value.Equal(gated.defaultValue) || policy.Version().Supports(gated.version) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root(gated.name),
+			"Unsupported namespace attribute",
+			fmt.Sprintf("The %s attribute requires ObjectScale or ECS %s or later, the cluster runs %s. Remove it from the configuration or upgrade the cluster.",
+				gated.name, gated.version, policy.Version()),
+		)
+	}
+}
+
 func (r *NamespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	tflog.Info(ctx, "creating namespace")
 	var plan models.NamespaceResourceModel
@@ -355,21 +473,37 @@ func (r *NamespaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultNamespaceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "Create", plan.Name.ValueString())
+	defer func() { endTrace(resp.Diagnostics) }()
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	namespace, err := helper.BuildNamespaceFromPlan(ctx, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Error building namespace from plan", err.Error())
 		return
 	}
 
-	namespace, err = r.client.ManagementClient.CreateNamespace(namespace)
+	api, err := r.api(plan.Endpoint)
+	if err != nil {
+		addEndpointError(&resp.Diagnostics, plan.Endpoint, err)
+		return
+	}
+
+	namespace, err = api.CreateNamespace(ctx, namespace)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating namespace", err.Error())
+		addOperationError(&resp.Diagnostics, "creating namespace", "create", createTimeout, err)
 		return
 	}
 
-	data := models.NamespaceEntity{}
-	err = helper.CopyFields(ctx, namespace, &data)
+	data := models.NamespaceResourceEntity{Endpoint: plan.Endpoint, Timeouts: plan.Timeouts}
+	err = helper.CopyFields(ctx, namespace, &data.NamespaceEntity)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting created namespace",
@@ -384,7 +518,7 @@ func (r *NamespaceResource) Create(ctx context.Context, req resource.CreateReque
 
 func (r *NamespaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	tflog.Info(ctx, "reading namespace")
-	var data models.NamespaceEntity
+	var data models.NamespaceResourceEntity
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -393,14 +527,38 @@ func (r *NamespaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	namespace, err := r.client.ManagementClient.GetNamespace(data.Name.ValueString())
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultNamespaceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "Read", data.Name.ValueString())
+	defer func() { endTrace(resp.Diagnostics) }()
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
+	api, err := r.api(data.Endpoint)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading namespace", err.Error())
+		addEndpointError(&resp.Diagnostics, data.Endpoint, err)
 		return
 	}
 
-	err = helper.CopyFields(ctx, namespace, &data)
+	namespace, err := api.GetNamespace(ctx, data.Name.ValueString())
+
+	if client.IsNotFound(err) {
+		// Deleted outside of Terraform, the namespace is planned for creation again.
+		tflog.Warn(ctx, "namespace not found, removing it from the state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addOperationError(&resp.Diagnostics, "reading namespace", "read", readTimeout, err)
+		return
+	}
+
+	err = helper.CopyFields(ctx, namespace, &data.NamespaceEntity)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting read namespace",
@@ -423,6 +581,16 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultNamespaceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "Update", plan.Name.ValueString())
+	defer func() { endTrace(resp.Diagnostics) }()
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	namespace, err := helper.BuildNamespaceFromPlan(ctx, &plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Error building namespace from plan", err.Error())
@@ -435,7 +603,7 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 	// and the non change value won't trigger the update
 	// For the update API, it should use the same get API to get the remote value,
 	// so just to refer get API definition to make sure all the required fields have the value assigned
-	var data models.NamespaceEntity
+	var data models.NamespaceResourceEntity
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -450,20 +618,26 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	_, err = r.client.ManagementClient.UpdateNamespace(namespace)
+	api, err := r.api(plan.Endpoint)
+	if err != nil {
+		addEndpointError(&resp.Diagnostics, plan.Endpoint, err)
+		return
+	}
+
+	_, err = api.UpdateNamespace(ctx, namespace)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating namespace", err.Error())
+		addOperationError(&resp.Diagnostics, "updating namespace", "update", updateTimeout, err)
 		return
 	}
 
-	namespace, err = r.client.ManagementClient.GetNamespace(namespace.Id)
+	namespace, err = api.GetNamespace(ctx, namespace.Id)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading namespace", err.Error())
+		addOperationError(&resp.Diagnostics, "reading namespace", "update", updateTimeout, err)
 		return
 	}
 
-	err = helper.CopyFields(ctx, namespace, &data)
+	err = helper.CopyFields(ctx, namespace, &data.NamespaceEntity)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting read namespace",
@@ -471,6 +645,8 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 		)
 		return
 	}
+	data.Endpoint = plan.Endpoint
+	data.Timeouts = plan.Timeouts
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -478,7 +654,7 @@ func (r *NamespaceResource) Update(ctx context.Context, req resource.UpdateReque
 
 func (r *NamespaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	tflog.Info(ctx, "deleting namespace")
-	var data models.NamespaceEntity
+	var data models.NamespaceResourceEntity
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -487,29 +663,70 @@ func (r *NamespaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	err := r.client.ManagementClient.DeleteNamespace(data.Id.ValueString())
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultNamespaceTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "Delete", data.Name.ValueString())
+	defer func() { endTrace(resp.Diagnostics) }()
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
+	api, err := r.api(data.Endpoint)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting namespace",
-			err.Error(),
-		)
+		addEndpointError(&resp.Diagnostics, data.Endpoint, err)
+		return
+	}
+
+	err = api.DeleteNamespace(ctx, data.Id.ValueString())
+
+	// Already deleted outside of Terraform.
+	if client.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		addOperationError(&resp.Diagnostics, "deleting namespace", "delete", deleteTimeout, err)
 	}
 }
 
+// ImportState imports the namespace of the ID, either <id> or <endpoint>,<id> for a namespace
+// whose calls are sent to another endpoint.
 func (r *NamespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	tflog.Info(ctx, "importing namespace")
 	id := req.ID
+	endpoint := types.StringNull()
+	if i := strings.LastIndex(id, ","); i >= 0 {
+		endpoint = types.StringValue(strings.TrimSpace(id[:i]))
+		id = strings.TrimSpace(id[i+1:])
+		if endpoint.ValueString() == "" || id == "" {
+			resp.Diagnostics.AddError(
+				"Invalid namespace import ID",
+				fmt.Sprintf("Expected the namespace identifier, or the endpoint and the identifier separated by a comma, ex. https://vdc2.example.com:4443,ns1, got %q.", req.ID),
+			)
+			return
+		}
+	}
 
-	namespace, err := r.client.ManagementClient.GetNamespace(id)
+	ctx, endTrace := traceOperation(ctx, namespaceTypeName, "ImportState", id)
+	defer func() { endTrace(resp.Diagnostics) }()
+	ctx, cancel := context.WithTimeout(ctx, defaultNamespaceTimeout)
+	defer cancel()
+
+	api, err := r.api(endpoint)
+	if err != nil {
+		addEndpointError(&resp.Diagnostics, endpoint, err)
+		return
+	}
+	namespace, err := api.GetNamespace(ctx, id)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading namespace", err.Error())
+		addOperationError(&resp.Diagnostics, "reading namespace", "import", defaultNamespaceTimeout, err)
 		return
 	}
 
-	data := models.NamespaceEntity{}
-	err = helper.CopyFields(ctx, namespace, &data)
+	data := models.NamespaceResourceEntity{Endpoint: endpoint, Timeouts: nullTimeouts()}
+	err = helper.CopyFields(ctx, namespace, &data.NamespaceEntity)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error converting imported namespace",
@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"terraform-provider-objectscale/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fakeClusterPolicy is a ManagementAPI enforcing a protection and reporting a version, without a cluster.
+type fakeClusterPolicy struct {
+	client.ManagementAPI
+	version   client.Version
+	protected map[string]bool
+}
+
+func (f *fakeClusterPolicy) CheckNamespaceDeletion(name string) error {
+	if f.protected[name] {
+		return fmt.Errorf("%w: %q is protected", client.ErrProtectedNamespace, name)
+	}
+	return nil
+}
+
+func (f *fakeClusterPolicy) Version() client.Version {
+	return f.version
+}
+
+// namespaceState returns a namespace resource value with the given attributes,
+// a null value when there are none.
+func namespaceState(t *testing.T, values map[string]interface{}) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	NewNamespaceResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	raw, err := types.ObjectNull(schemaResp.Schema.Type().(types.ObjectType).AttrTypes).ToTerraformValue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+	for name, value := range values {
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+	return state
+}
+
+// modifyNamespacePlan runs ModifyPlan from the state to the configuration, planned as is.
+func modifyNamespacePlan(t *testing.T, api client.ManagementAPI, state, config map[string]interface{}) diag.Diagnostics {
+	t.Helper()
+	planned := namespaceState(t, config)
+	req := resource.ModifyPlanRequest{
+		State:  namespaceState(t, state),
+		Config: tfsdk.Config{Schema: planned.Schema, Raw: planned.Raw},
+		Plan:   tfsdk.Plan{Schema: planned.Schema, Raw: planned.Raw},
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	(&NamespaceResource{client: api}).ModifyPlan(context.Background(), req, &resp)
+	return resp.Diagnostics
+}
+
+func TestModifyPlanRefusesToDestroyProtectedNamespaces(t *testing.T) {
+	api := &fakeClusterPolicy{protected: map[string]bool{"prod-1": true}}
+
+	tests := []struct {
+		name      string
+		state     map[string]interface{}
+		config    map[string]interface{}
+		wantError bool
+	}{
+		{name: "destroy protected", state: map[string]interface{}{"id": "prod-1", "name": "prod-1"}, wantError: true},
+		{name: "destroy unprotected", state: map[string]interface{}{"id": "test-1", "name": "test-1"}},
+		{name: "update protected", state: map[string]interface{}{"id": "prod-1", "name": "prod-1"}, config: map[string]interface{}{"id": "prod-1", "name": "prod-1"}},
+		{name: "create", config: map[string]interface{}{"name": "prod-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := modifyNamespacePlan(t, api, tt.state, tt.config)
+			refused := false
+			for _, d := range diags.Errors() {
+				refused = refused || d.Summary() == "Protected namespace"
+			}
+			if refused != tt.wantError {
+				t.Errorf("got %v, want the destruction refused %v", diags, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestModifyPlanRejectsAttributesUnsupportedByTheCluster(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   client.Version
+		config    map[string]interface{}
+		wantError string
+	}{
+		{name: "unknown version", config: map[string]interface{}{"is_object_lock_with_ado_allowed": true}},
+		{name: "supported", version: client.Version{Major: 3, Minor: 8}, config: map[string]interface{}{"is_object_lock_with_ado_allowed": true, "default_audit_delete_expiration": int64(7)}},
+		{name: "unset", version: client.Version{Major: 3, Minor: 6}},
+		{name: "explicit defaults", version: client.Version{Major: 3, Minor: 6}, config: map[string]interface{}{"is_object_lock_with_ado_allowed": false, "default_audit_delete_expiration": int64(0)}},
+		{name: "object lock with ADO", version: client.Version{Major: 3, Minor: 7}, config: map[string]interface{}{"is_object_lock_with_ado_allowed": true, "default_audit_delete_expiration": int64(7)}, wantError: "is_object_lock_with_ado_allowed"},
+		{name: "audit delete expiration", version: client.Version{Major: 3, Minor: 6}, config: map[string]interface{}{"default_audit_delete_expiration": int64(7)}, wantError: "default_audit_delete_expiration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"name": "ns1"}
+			for name, value := range tt.config {
+				config[name] = value
+			}
+			diags := modifyNamespacePlan(t, &fakeClusterPolicy{version: tt.version}, nil, config)
+
+			var rejected []string
+			for _, d := range diags.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				if !ok || d.Summary() != "Unsupported namespace attribute" {
+					t.Fatalf("unexpected error %v", d)
+				}
+				rejected = append(rejected, withPath.Path().String())
+			}
+			if tt.wantError == "" && len(rejected) != 0 || tt.wantError != "" && (len(rejected) != 1 || rejected[0] != tt.wantError) {
+				t.Errorf("got %v rejected, want %q", rejected, tt.wantError)
+			}
+		})
+	}
+}
+
+// fakeNamespaces is a ManagementAPI holding the namespaces of one endpoint, and the
+// other endpoints by URL.
+type fakeNamespaces struct {
+	client.ManagementAPI
+	namespaces map[string]client.Namespace
+	endpoints  map[string]*fakeNamespaces
+}
+
+func (f *fakeNamespaces) GetNamespace(ctx context.Context, id string) (*client.Namespace, error) {
+	namespace, ok := f.namespaces[id]
+	if !ok {
+		return nil, fmt.Errorf("namespace %q not found", id)
+	}
+	return &namespace, nil
+}
+
+func (f *fakeNamespaces) ForEndpoint(endpoint string) (client.ManagementAPI, error) {
+	api, ok := f.endpoints[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("unable to log in to %s", endpoint)
+	}
+	return api, nil
+}
+
+func TestImportStateWithAnEndpoint(t *testing.T) {
+	remote := &fakeNamespaces{namespaces: map[string]client.Namespace{"ns2": {Id: "ns2", Name: "ns2"}}}
+	api := &fakeNamespaces{
+		namespaces: map[string]client.Namespace{"ns1": {Id: "ns1", Name: "ns1"}},
+		endpoints:  map[string]*fakeNamespaces{"https://vdc2:4443": remote},
+	}
+
+	tests := []struct {
+		id           string
+		wantName     string
+		wantEndpoint types.String
+		wantError    string
+	}{
+		{id: "ns1", wantName: "ns1", wantEndpoint: types.StringNull()},
+		{id: "https://vdc2:4443,ns2", wantName: "ns2", wantEndpoint: types.StringValue("https://vdc2:4443")},
+		{id: " https://vdc2:4443 , ns2 ", wantName: "ns2", wantEndpoint: types.StringValue("https://vdc2:4443")},
+		{id: "https://vdc2:4443,ns1", wantError: "Error reading namespace"},
+		{id: "https://vdc3:4443,ns2", wantError: "Unable to connect to the namespace endpoint"},
+		{id: ",ns1", wantError: "Invalid namespace import ID"},
+		{id: "https://vdc2:4443,", wantError: "Invalid namespace import ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			resp := resource.ImportStateResponse{State: namespaceState(t, nil)}
+			(&NamespaceResource{client: api}).ImportState(context.Background(), resource.ImportStateRequest{ID: tt.id}, &resp)
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("got %v, want %q", resp.Diagnostics, tt.wantError)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+			var name, endpoint types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("name"), &name)...)
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("endpoint"), &endpoint)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+			if name.ValueString() != tt.wantName || !endpoint.Equal(tt.wantEndpoint) {
+				t.Errorf("imported %s at %s, want %s at %s", name, endpoint, tt.wantName, tt.wantEndpoint)
+			}
+		})
+	}
+}
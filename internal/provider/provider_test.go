@@ -0,0 +1,202 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMergeProfileToken(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config")
+	profile := "[prod]\nendpoint = https://objectscale.example.com:4443\nusername = profile-user\ntoken = profile-token\n"
+	if err := os.WriteFile(config, []byte(profile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envConfigFile, config)
+
+	tests := []struct {
+		name      string
+		data      ObjectScaleProviderModel
+		wantToken types.String
+		wantUser  string
+	}{
+		{
+			name:      "nothing set",
+			wantToken: types.StringValue("profile-token"),
+			wantUser:  "profile-user",
+		},
+		{
+			name:      "explicit username and password",
+			data:      ObjectScaleProviderModel{Username: types.StringValue("admin"), Password: types.StringValue("secret")},
+			wantToken: types.StringNull(),
+			wantUser:  "admin",
+		},
+		{
+			name:      "explicit password",
+			data:      ObjectScaleProviderModel{Password: types.StringValue("secret")},
+			wantToken: types.StringNull(),
+			wantUser:  "profile-user",
+		},
+		{
+			name:      "credential process",
+			data:      ObjectScaleProviderModel{CredentialProcess: types.StringValue("vault-objectscale")},
+			wantToken: types.StringNull(),
+			wantUser:  "profile-user",
+		},
+		{
+			name:      "explicit token",
+			data:      ObjectScaleProviderModel{AuthToken: types.StringValue("argument-token")},
+			wantToken: types.StringValue("argument-token"),
+			wantUser:  "profile-user",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			data := test.data
+			mergeProfile(context.Background(), &diags, &data, "prod")
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !data.AuthToken.Equal(test.wantToken) {
+				t.Errorf("auth_token = %s, want %s", data.AuthToken, test.wantToken)
+			}
+			if data.Username.ValueString() != test.wantUser {
+				t.Errorf("username = %q, want %q", data.Username.ValueString(), test.wantUser)
+			}
+		})
+	}
+}
+
+// configureProvider runs Configure with the given attributes, the others are null.
+func configureProvider(t *testing.T, values map[string]attr.Value) diag.Diagnostics {
+	t.Helper()
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	attrTypes := schemaResp.Schema.Type().(types.ObjectType).AttrTypes
+	objectValues := map[string]attr.Value{}
+	for name, attrType := range attrTypes {
+		if value, ok := values[name]; ok {
+			objectValues[name] = value
+			continue
+		}
+		switch attrType {
+		case types.StringType:
+			objectValues[name] = types.StringNull()
+		case types.BoolType:
+			objectValues[name] = types.BoolNull()
+		case types.Int64Type:
+			objectValues[name] = types.Int64Null()
+		default:
+			objectValues[name] = types.ListNull(attrType.(types.ListType).ElemType)
+		}
+	}
+	object, diags := types.ObjectValue(attrTypes, objectValues)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	raw, err := object.ToTerraformValue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}}, &resp)
+	return resp.Diagnostics
+}
+
+// hasAttributeError reports whether the diagnostics hold an error with the summary on the attribute.
+func hasAttributeError(diags diag.Diagnostics, attribute, summary string) bool {
+	for _, d := range diags.Errors() {
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(path.Root(attribute)) && d.Summary() == summary {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConfigureRejectsUnknownValues(t *testing.T) {
+	tests := map[string]attr.Value{
+		"endpoint_selection":      types.StringUnknown(),
+		"max_retries":             types.Int64Unknown(),
+		"retry_min_backoff":       types.StringUnknown(),
+		"retry_max_backoff":       types.StringUnknown(),
+		"max_concurrent_requests": types.Int64Unknown(),
+	}
+	for attribute, value := range tests {
+		t.Run(attribute, func(t *testing.T) {
+			diags := configureProvider(t, map[string]attr.Value{
+				"endpoint": types.StringValue("https://objectscale.example.com:4443"),
+				"username": types.StringValue("root"),
+				"password": types.StringValue("password"),
+				attribute:  value,
+			})
+			if !hasAttributeError(diags, attribute, "Unknown objectscale "+attribute) {
+				t.Fatalf("got %v, want an error on %s", diags, attribute)
+			}
+		})
+	}
+}
+
+func TestConfigureRefusesNativeOnlySettingsWithTheBinding(t *testing.T) {
+	tests := map[string]attr.Value{
+		"ca_certificate":    types.StringValue("-----BEGIN CERTIFICATE-----"),
+		"auth_token":        types.StringValue("token"),
+		"token_cache_file":  types.StringValue(filepath.Join(t.TempDir(), "tokens.json")),
+		"proxy_url":         types.StringValue("http://proxy:3128"),
+		"max_retries":       types.Int64Value(5),
+		"retry_min_backoff": types.StringValue("2s"),
+		"retry_max_backoff": types.StringValue("1m"),
+		"endpoints": types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("https://a.example.com:4443"),
+			types.StringValue("https://b.example.com:4443"),
+		}),
+	}
+	for attribute, value := range tests {
+		t.Run(attribute, func(t *testing.T) {
+			values := map[string]attr.Value{
+				"endpoint": types.StringValue("https://objectscale.example.com:4443"),
+				"username": types.StringValue("root"),
+				"password": types.StringValue("password"),
+				"backend":  types.StringValue("binding"),
+				attribute:  value,
+			}
+			if attribute == "endpoints" {
+				delete(values, "endpoint")
+			}
+			diags := configureProvider(t, values)
+			if !hasAttributeError(diags, attribute, "Unsupported objectscale setting for the binding backend") {
+				t.Fatalf("got %v, want an error on %s", diags, attribute)
+			}
+		})
+	}
+}
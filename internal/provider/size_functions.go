@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"terraform-provider-objectscale/internal/helper"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the size functions satisfy the function interface.
+var (
+	_ function.Function = &convertSizeFunction{}
+	_ function.Function = &quotaSizeFunction{}
+)
+
+// sizeUnitsDescription lists the units accepted by the size functions.
+const sizeUnitsDescription = "One of `B`, `KB`, `MB`, `GB`, `TB`, `PB`, `KiB`, `MiB`, `GiB`, `TiB` or `PiB`, case insensitive."
+
+// convertSizeFunction converts a size between two units.
+type convertSizeFunction struct{}
+
+// NewConvertSizeFunction returns the convert_size function.
+func NewConvertSizeFunction() function.Function {
+	return &convertSizeFunction{}
+}
+
+// Metadata describes the function name.
+func (f *convertSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "convert_size"
+}
+
+// Definition describes the function arguments and result.
+func (f *convertSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a size between two units.",
+		MarkdownDescription: "Convert a size between two units, decimal like `GB` or binary like `GiB`, ex. `convert_size(1, \"GiB\", \"GB\")` returns `1.073741824`.",
+		Parameters: []function.Parameter{
+			function.Float64Parameter{
+				Name:                "size",
+				MarkdownDescription: "The size to convert.",
+			},
+			function.StringParameter{
+				Name:                "from",
+				MarkdownDescription: "The unit of the size. " + sizeUnitsDescription,
+			},
+			function.StringParameter{
+				Name:                "to",
+				MarkdownDescription: "The unit to convert to. " + sizeUnitsDescription,
+			},
+		},
+		Return: function.Float64Return{},
+	}
+}
+
+// Run converts the size.
+func (f *convertSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size float64
+	var from, to string
+	resp.Error = req.Arguments.Get(ctx, &size, &from, &to)
+	if resp.Error != nil {
+		return
+	}
+
+	converted, err := helper.ConvertSize(size, from, to)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, converted)
+}
+
+// quotaSizeFunction converts a size to the unit of the namespace quotas.
+type quotaSizeFunction struct{}
+
+// NewQuotaSizeFunction returns the quota_size function.
+func NewQuotaSizeFunction() function.Function {
+	return &quotaSizeFunction{}
+}
+
+// Metadata describes the function name.
+func (f *quotaSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "quota_size"
+}
+
+// Definition describes the function arguments and result.
+func (f *quotaSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a size to the unit of the namespace quotas.",
+		MarkdownDescription: "Convert a size to whole " + helper.QuotaUnit + ", the unit of the `block_size` and `notification_size` namespace quotas. The result is rounded up, so the quota is never smaller than the size, ex. `quota_size(500, \"GiB\")` returns `537`.",
+		Parameters: []function.Parameter{
+			function.Float64Parameter{
+				Name:                "size",
+				MarkdownDescription: "The size to convert, not negative.",
+			},
+			function.StringParameter{
+				Name:                "unit",
+				MarkdownDescription: "The unit of the size. " + sizeUnitsDescription,
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+// Run converts the size.
+func (f *quotaSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size float64
+	var unit string
+	resp.Error = req.Arguments.Get(ctx, &size, &unit)
+	if resp.Error != nil {
+		return
+	}
+
+	quota, err := helper.QuotaSize(size, unit)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, quota)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"terraform-provider-objectscale/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Environment variables enabling the tracing. The OTLP exporter also reads the
+// other OTEL_EXPORTER_OTLP_* variables, ex. for headers or certificates.
+const (
+	envTraceFile          = "OBJECTSCALE_TRACE_FILE"
+	envOTLPEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	envOTELSDKDisabled    = "OTEL_SDK_DISABLED"
+)
+
+// flushTimeout bounds the export of the spans at the end of an operation, so an
+// unreachable collector does not hold up the operations.
+const flushTimeout = 2 * time.Second
+
+// tracing holds the tracer provider, installed once per provider process.
+var tracing struct {
+	once     sync.Once
+	provider *sdktrace.TracerProvider
+	err      error
+}
+
+// setupTracing installs the tracer provider when the tracing is enabled by the environment.
+func setupTracing(ctx context.Context) error {
+	tracing.once.Do(func() {
+		tracing.provider, tracing.err = newTracerProvider(ctx)
+		if tracing.provider != nil {
+			otel.SetTracerProvider(tracing.provider)
+		}
+	})
+	return tracing.err
+}
+
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	if disabled, _ := strconv.ParseBool(os.Getenv(envOTELSDKDisabled)); disabled {
+		return nil, nil
+	}
+
+	var options []sdktrace.TracerProviderOption
+	if path := os.Getenv(envTraceFile); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, err
+		}
+		// Spans are written as they end, the provider process may exit at any time.
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+	if os.Getenv(envOTLPEndpoint) != "" || os.Getenv(envOTLPTracesEndpoint) != "" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the OTLP exporter: %w", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	res, err := resource.Merge(
+		resource.NewSchemaless(attribute.String("service.name", "terraform-provider-objectscale")),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, sdktrace.WithResource(res))
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// traceOperation opens the span of a data source or resource method. The returned
+// function ends it with the outcome of the method, and flushes the exported spans
+// as the provider process gets no notice before it exits.
+func traceOperation(ctx context.Context, resourceType, method, namespace string) (context.Context, func(diags diag.Diagnostics)) {
+	ctx, span := otel.Tracer(client.TracerName).Start(ctx, resourceType+"."+method,
+		trace.WithAttributes(client.AttributeResourceType.String(resourceType)))
+	if namespace != "" {
+		span.SetAttributes(client.AttributeNamespace.String(namespace))
+	}
+
+	return ctx, func(diags diag.Diagnostics) {
+		if errs := diags.Errors(); len(errs) > 0 {
+			span.SetStatus(codes.Error, errs[0].Summary())
+		}
+		span.End()
+		if tracing.provider != nil {
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			defer cancel()
+			_ = tracing.provider.ForceFlush(flushCtx)
+		}
+	}
+}
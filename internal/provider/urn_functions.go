@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2023-2024 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"terraform-provider-objectscale/internal/helper"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the URN functions satisfy the function interface.
+var (
+	_ function.Function = &parseURNFunction{}
+	_ function.Function = &buildURNFunction{}
+)
+
+// urnAttributeTypes are the attributes of a parsed URN.
+var urnAttributeTypes = map[string]attr.Type{
+	"type":  types.StringType,
+	"uuid":  types.StringType,
+	"scope": types.StringType,
+}
+
+// parseURNFunction splits an ObjectScale URN in its parts.
+type parseURNFunction struct{}
+
+// NewParseURNFunction returns the parse_urn function.
+func NewParseURNFunction() function.Function {
+	return &parseURNFunction{}
+}
+
+// Metadata describes the function name.
+func (f *parseURNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_urn"
+}
+
+// Definition describes the function arguments and result.
+func (f *parseURNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Split an ObjectScale URN in its type, UUID and scope.",
+		MarkdownDescription: "Split an ObjectScale URN, ex. `urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global`, in an object with its `type`, `uuid` and `scope`. The scope is empty for the objects of the local VDC.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "urn",
+				MarkdownDescription: "The URN to split.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: urnAttributeTypes,
+		},
+	}
+}
+
+// Run splits the URN.
+func (f *parseURNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var urn string
+	resp.Error = req.Arguments.Get(ctx, &urn)
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := helper.ParseURN(urn)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, parsed)
+}
+
+// buildURNFunction joins the parts of an ObjectScale URN.
+type buildURNFunction struct{}
+
+// NewBuildURNFunction returns the build_urn function.
+func NewBuildURNFunction() function.Function {
+	return &buildURNFunction{}
+}
+
+// Metadata describes the function name.
+func (f *buildURNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "build_urn"
+}
+
+// Definition describes the function arguments and result.
+func (f *buildURNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Build an ObjectScale URN from its type, UUID and scope.",
+		MarkdownDescription: "Build an ObjectScale URN from its type, UUID and scope, ex. `build_urn(\"ReplicationGroupInfo\", \"0e953ad1-94a5-4eb1-825a-d58d29e85434\", \"global\")` returns `urn:storageos:ReplicationGroupInfo:0e953ad1-94a5-4eb1-825a-d58d29e85434:global`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "type",
+				MarkdownDescription: "The kind of object, ex. `ReplicationGroupInfo`.",
+			},
+			function.StringParameter{
+				Name:                "uuid",
+				MarkdownDescription: "The UUID of the object.",
+			},
+			function.StringParameter{
+				Name:                "scope",
+				MarkdownDescription: "The VDC owning the object, `global`, or an empty string for the local VDC.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run builds the URN.
+func (f *buildURNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var urnType, uuid, scope string
+	resp.Error = req.Arguments.Get(ctx, &urnType, &uuid, &scope)
+	if resp.Error != nil {
+		return
+	}
+
+	urn, err := helper.BuildURN(urnType, uuid, scope)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, urn)
+}
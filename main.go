@@ -20,8 +20,10 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 
+	"terraform-provider-objectscale/internal/client"
 	"terraform-provider-objectscale/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -48,10 +50,21 @@ var (
 
 func main() {
 	var debug bool
+	var auditLog string
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.StringVar(&auditLog, "verify-audit-log", "", "check the hash chain of the audit log at this path, print the hash of its last entry and exit")
 	flag.Parse()
 
+	if auditLog != "" {
+		lastHash, err := client.VerifyAuditLog(auditLog)
+		if err != nil {
+			log.Fatalf("unable to verify the audit log %s: %s", auditLog, err.Error())
+		}
+		fmt.Printf("the audit log %s is intact, the hash of its last entry is %q\n", auditLog, lastHash)
+		return
+	}
+
 	opts := providerserver.ServeOpts{
 		Address: "registry.terraform.io/dell/objectscale",
 		Debug:   debug,